@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"database-classifier/internal/config"
+	"database-classifier/internal/domain"
 	"database-classifier/internal/handler"
 	"database-classifier/internal/infrastructure/database"
 	httpInfra "database-classifier/internal/infrastructure/http"
@@ -40,28 +41,79 @@ func main() {
     }
     defer metadataDB.Close()
 
+    migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 10*time.Minute)
+    err = repository.Migrate(migrateCtx, metadataDB)
+    cancelMigrate()
+    if err != nil {
+        log.Fatalf("Failed to migrate metadata database: %v", err)
+    }
+
     // Initialize repositories
     dbConnRepo := repository.NewDatabaseConnectionRepository(metadataDB)
     scanRepo := repository.NewScanResultRepository(metadataDB)
+    scanJobRepo := repository.NewScanJobRepository(metadataDB)
+    scheduleRepo := repository.NewScanScheduleRepository(metadataDB)
+    leaseRepo := repository.NewLeaderLeaseRepository(metadataDB)
+    findingRepo := repository.NewFindingRepository(metadataDB)
     patternRepo := repository.NewClassificationPatternRepository(metadataDB)
+    abbreviationRepo := repository.NewAbbreviationRepository(metadataDB)
+    infoTypeRepo := repository.NewInformationTypeRepository(metadataDB)
+    riskPolicyRepo := repository.NewRiskPolicyRepository(metadataDB)
 
     // Initialize services
     ctx := context.Background()
-    classificationService, err := service.NewClassificationService(ctx, patternRepo, "configs/patterns.json")
+    classificationService, err := service.NewClassificationService(ctx, patternRepo, abbreviationRepo, infoTypeRepo, "configs/patterns.json", "configs/abbreviations.json", "configs/information_types.json", "configs/packs")
     if err != nil {
         log.Fatalf("Failed to initialize classification service: %v", err)
     }
 
-    databaseService := service.NewDatabaseService(dbConnRepo, encryptor)
-    scanService := service.NewScanService(scanRepo, dbConnRepo, encryptor, classificationService)
+    databaseService := service.NewDatabaseService(dbConnRepo, riskPolicyRepo, encryptor, service.SQLiteOptions{
+        UploadDir:   cfg.SQLite.UploadDir,
+        AllowedDirs: cfg.SQLite.AllowedDirs,
+    })
+    findingService := service.NewFindingService(findingRepo)
+    riskService := service.NewRiskService(riskPolicyRepo, dbConnRepo, scanRepo, classificationService)
+    instanceID := workerID()
+    scanService := service.NewScanService(scanRepo, scanJobRepo, dbConnRepo, encryptor, classificationService, riskPolicyRepo, service.ScanQueueOptions{
+        WorkerID:            instanceID,
+        MaxConcurrent:       cfg.Scan.MaxConcurrent,
+        MaxPerTarget:        cfg.Scan.MaxPerTarget,
+        TableConcurrency:    cfg.Scan.TableConcurrency,
+        TolerateTableErrors: cfg.Scan.TolerateTableErrors,
+        RecoveryPolicy:      domain.RecoveryPolicy(cfg.Scan.RecoveryPolicy),
+        StaleAfter:          cfg.Scan.StaleAfter,
+    })
+
+    // Reconcile scans left pending or running by a previous process; the
+    // workers keep doing so for replicas that stop while this one runs
+    recoveryCtx, cancelRecovery := context.WithTimeout(ctx, cfg.API.Timeout)
+    report, err := scanService.RecoverOrphanedScans(recoveryCtx, domain.RecoveryPolicy(cfg.Scan.RecoveryPolicy), cfg.Scan.StaleAfter)
+    cancelRecovery()
+    if err != nil {
+        log.Printf("Warning: failed to recover orphaned scans: %v", err)
+    } else if report.Found > 0 {
+        log.Printf("Recovered %d orphaned scans (policy=%s, failed=%d, requeued=%d)", report.Found, report.Policy, report.Failed, report.Requeued)
+    }
+
+    workerCtx, stopWorkers := context.WithCancel(ctx)
+    defer stopWorkers()
+    scanService.StartWorkers(workerCtx)
+
+    scheduleService := service.NewScheduleService(scheduleRepo, leaseRepo, dbConnRepo, scanService, instanceID)
+    if cfg.Scheduler.Enabled {
+        go scheduleService.Run(workerCtx, cfg.Scheduler.PollInterval)
+    }
 
     // Initialize handlers
-    databaseHandler := handler.NewDatabaseHandler(databaseService)
+    databaseHandler := handler.NewDatabaseHandler(databaseService, cfg.SQLite.MaxUploadBytes)
     scanHandler := handler.NewScanHandler(scanService)
     classificationHandler := handler.NewClassificationHandler(classificationService)
+    scheduleHandler := handler.NewScheduleHandler(scheduleService)
+    findingHandler := handler.NewFindingHandler(findingService)
+    riskHandler := handler.NewRiskHandler(riskService)
 
 	// Setup router
-    router := httpInfra.NewRouter(databaseHandler, scanHandler, classificationHandler)
+    router := httpInfra.NewRouter(databaseHandler, scanHandler, classificationHandler, scheduleHandler, findingHandler, riskHandler)
 	engine := router.SetupRoutes()
 
 	// Create HTTP server
@@ -85,6 +137,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -95,3 +148,12 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// workerID identifies this process in the scan queue and scheduler lease.
+func workerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
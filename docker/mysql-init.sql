@@ -144,7 +144,14 @@ CREATE TABLE IF NOT EXISTS database_connections (
     created_at DATETIME(6) NOT NULL,
     updated_at DATETIME(6) NOT NULL,
     last_scanned_at DATETIME(6) NULL,
-    is_active TINYINT(1) NOT NULL DEFAULT 1
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    sample_size INT NOT NULL DEFAULT 0,
+    engine VARCHAR(16) NOT NULL DEFAULT 'mysql',
+    file_path VARCHAR(1024) NULL,
+    scan_scope TEXT NULL,
+    scan_concurrency INT NOT NULL DEFAULT 0,
+    pattern_packs TEXT NULL,
+    risk_policy_id CHAR(36) NULL
 );
 
 CREATE TABLE IF NOT EXISTS scan_results (
@@ -152,26 +159,146 @@ CREATE TABLE IF NOT EXISTS scan_results (
     database_id CHAR(36) NOT NULL,
     started_at DATETIME(6) NOT NULL,
     completed_at DATETIME(6) NULL,
+    heartbeat_at DATETIME(6) NULL,
     status VARCHAR(32) NOT NULL,
     error_message TEXT,
     schemas_json LONGTEXT NULL,
     summary_json LONGTEXT NULL,
+    scope_json TEXT NULL,
+    progress_json TEXT NULL,
+    errors_json TEXT NULL,
+    incremental TINYINT(1) NOT NULL DEFAULT 0,
+    base_scan_id CHAR(36) NULL,
+    scope_overridden TINYINT(1) NOT NULL DEFAULT 0,
     INDEX idx_scan_database (database_id),
     INDEX idx_scan_status (status),
     INDEX idx_scan_started_at (started_at)
 );
 
+CREATE TABLE IF NOT EXISTS scan_tables (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    scan_id CHAR(36) NOT NULL,
+    database_id CHAR(36) NOT NULL,
+    schema_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    table_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    total_columns INT NOT NULL,
+    classified_columns INT NOT NULL,
+    UNIQUE KEY uq_scan_tables_table (scan_id, schema_name, table_name),
+    INDEX idx_scan_tables_database (database_id),
+    CONSTRAINT fk_scan_tables_scan FOREIGN KEY (scan_id) REFERENCES scan_results (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS scan_columns (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    scan_id CHAR(36) NOT NULL,
+    database_id CHAR(36) NOT NULL,
+    schema_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    table_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    column_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    data_type TEXT NOT NULL,
+    information_type VARCHAR(64) NOT NULL,
+    confidence_score DOUBLE NOT NULL,
+    matched_patterns TEXT NULL,
+    is_nullable TINYINT(1) NOT NULL,
+    sample_size INT NOT NULL DEFAULT 0,
+    matched_samples INT NOT NULL DEFAULT 0,
+    INDEX idx_scan_columns_scan (scan_id, schema_name, table_name),
+    INDEX idx_scan_columns_database (database_id),
+    INDEX idx_scan_columns_type (information_type, confidence_score),
+    INDEX idx_scan_columns_confidence (confidence_score),
+    CONSTRAINT fk_scan_columns_scan FOREIGN KEY (scan_id) REFERENCES scan_results (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS scan_jobs (
+    scan_id CHAR(36) PRIMARY KEY,
+    database_id CHAR(36) NOT NULL,
+    target VARCHAR(512) NOT NULL,
+    enqueued_at DATETIME(6) NOT NULL,
+    claimed_by VARCHAR(255) NULL,
+    claimed_at DATETIME(6) NULL,
+    UNIQUE KEY uq_scan_jobs_database (database_id),
+    INDEX idx_scan_jobs_enqueued_at (enqueued_at)
+);
+
+CREATE TABLE IF NOT EXISTS scan_schedules (
+    id CHAR(36) PRIMARY KEY,
+    database_id CHAR(36) NOT NULL,
+    cron_expression VARCHAR(128) NULL,
+    interval_seconds INT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    is_paused TINYINT(1) NOT NULL DEFAULT 0,
+    incremental TINYINT(1) NOT NULL DEFAULT 0,
+    next_run_at DATETIME(6) NOT NULL,
+    last_run_at DATETIME(6) NULL,
+    last_scan_id CHAR(36) NULL,
+    created_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NOT NULL,
+    INDEX idx_schedule_database (database_id),
+    INDEX idx_schedule_due (is_paused, next_run_at)
+);
+
+CREATE TABLE IF NOT EXISTS leader_leases (
+    name VARCHAR(64) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at DATETIME(6) NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS classification_patterns (
     id CHAR(36) PRIMARY KEY,
     information_type VARCHAR(64) NOT NULL,
     pattern VARCHAR(255) NOT NULL UNIQUE,
     description TEXT,
     priority INT NOT NULL,
+    validator VARCHAR(64) NULL,
+    target VARCHAR(16) NULL,
+    match_tokens TINYINT(1) NOT NULL DEFAULT 0,
+    pack VARCHAR(64) NULL,
+    table_pattern VARCHAR(255) NULL,
+    co_columns TEXT NULL,
+    context_boost DOUBLE NOT NULL DEFAULT 0,
+    allowed_data_types TEXT NULL,
+    disallowed_data_types TEXT NULL,
+    min_length INT NULL,
+    max_length INT NULL,
     is_active TINYINT(1) NOT NULL DEFAULT 1,
     created_at DATETIME(6) NOT NULL,
     updated_at DATETIME(6) NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS abbreviations (
+    id CHAR(36) PRIMARY KEY,
+    abbreviation VARCHAR(64) NOT NULL UNIQUE,
+    expansion VARCHAR(255) NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS information_types (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT,
+    sensitivity VARCHAR(16) NOT NULL,
+    parent VARCHAR(64) NULL,
+    regulatory_tags TEXT NULL,
+    created_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS risk_policies (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT,
+    type_weights TEXT NULL,
+    tier_weights TEXT NULL,
+    min_confidence DOUBLE NOT NULL DEFAULT 0,
+    table_aggregation VARCHAR(16) NOT NULL,
+    database_aggregation VARCHAR(16) NOT NULL,
+    medium_threshold DOUBLE NOT NULL,
+    high_threshold DOUBLE NOT NULL,
+    critical_threshold DOUBLE NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NOT NULL
+);
+
 CREATE USER IF NOT EXISTS 'metauser'@'%' IDENTIFIED BY 'metapass';
 GRANT ALL PRIVILEGES ON classifier_meta.* TO 'metauser'@'%';
 FLUSH PRIVILEGES;
@@ -0,0 +1,57 @@
+-- Sample PostgreSQL target with schemas, a partitioned table and custom types
+
+CREATE SCHEMA IF NOT EXISTS crm;
+CREATE SCHEMA IF NOT EXISTS billing;
+
+CREATE TYPE crm.customer_status AS ENUM ('active', 'inactive', 'blocked');
+
+CREATE TABLE crm.customers (
+    customer_id SERIAL PRIMARY KEY,
+    first_name VARCHAR(50),
+    last_name VARCHAR(50),
+    email_address VARCHAR(255) UNIQUE,
+    phone_number VARCHAR(20),
+    date_of_birth DATE,
+    national_id VARCHAR(20),
+    status crm.customer_status NOT NULL DEFAULT 'active',
+    tags TEXT[],
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE crm.access_logs (
+    log_id BIGSERIAL PRIMARY KEY,
+    customer_id INT REFERENCES crm.customers (customer_id),
+    ip_address INET,
+    mac_address MACADDR,
+    login_time TIMESTAMPTZ
+);
+
+-- Partitions are scanned through their parent table
+CREATE TABLE billing.payments (
+    payment_id BIGINT NOT NULL,
+    customer_id INT NOT NULL,
+    credit_card_number VARCHAR(20),
+    bank_account VARCHAR(30),
+    amount NUMERIC(10,2),
+    paid_at DATE NOT NULL,
+    PRIMARY KEY (payment_id, paid_at)
+) PARTITION BY RANGE (paid_at);
+
+CREATE TABLE billing.payments_2025 PARTITION OF billing.payments
+    FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');
+CREATE TABLE billing.payments_2026 PARTITION OF billing.payments
+    FOR VALUES FROM ('2026-01-01') TO ('2027-01-01');
+
+INSERT INTO crm.customers (first_name, last_name, email_address, phone_number, date_of_birth, national_id, tags) VALUES
+('Ana', 'García', 'ana.garcia@example.com', '+34-600-000-001', '1988-04-12', 'X1234567L', ARRAY['vip']),
+('Luis', 'Pérez', 'luis.perez@example.com', '+34-600-000-002', '1979-11-30', 'Y7654321M', NULL);
+
+INSERT INTO crm.access_logs (customer_id, ip_address, mac_address, login_time) VALUES
+(1, '192.168.1.10', '00:1b:44:11:3a:b7', now()),
+(2, '10.0.0.25', '00:1b:44:11:3a:b8', now());
+
+INSERT INTO billing.payments (payment_id, customer_id, credit_card_number, bank_account, amount, paid_at) VALUES
+(1, 1, '4111-1111-1111-1111', 'ES9121000418450200051332', 120.50, '2025-06-01'),
+(2, 2, '5555-5555-5555-4444', 'ES7921000813610123456789', 75.00, '2026-02-15');
+
+ANALYZE;
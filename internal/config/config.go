@@ -4,17 +4,28 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+
+	"database-classifier/internal/domain"
 )
 
+// minStaleAfterHeartbeats is how many heartbeats a running scan may miss
+// before it can be taken for an orphan, so that a slow heartbeat write does
+// not get a healthy scan reclaimed.
+const minStaleAfterHeartbeats = 3
+
 type Config struct {
     Server     ServerConfig
     MetadataDB MetadataDBConfig
     Security   SecurityConfig
     Logging    LoggingConfig
     API        APIConfig
+    Scan       ScanConfig
+    Scheduler  SchedulerConfig
+    SQLite     SQLiteConfig
 }
 
 type ServerConfig struct {
@@ -46,6 +57,32 @@ type APIConfig struct {
 	Timeout time.Duration
 }
 
+type ScanConfig struct {
+	RecoveryPolicy string
+	StaleAfter     time.Duration
+	MaxConcurrent  int
+	MaxPerTarget   int
+
+	// TableConcurrency is the number of tables a scan classifies at once when
+	// its connection does not set scan_concurrency.
+	TableConcurrency int
+
+	// TolerateTableErrors records schemas and tables that cannot be read and
+	// completes the scan without them instead of failing it.
+	TolerateTableErrors bool
+}
+
+type SchedulerConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
+}
+
+type SQLiteConfig struct {
+	UploadDir      string
+	AllowedDirs    []string
+	MaxUploadBytes int64
+}
+
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("Warning: .env file not found, using environment variables")
@@ -76,6 +113,23 @@ func Load() (*Config, error) {
             Version: getStringEnv("API_VERSION", "v1"),
             Timeout: getDurationEnv("API_TIMEOUT", 30*time.Second),
         },
+        Scan: ScanConfig{
+            RecoveryPolicy:      getStringEnv("SCAN_RECOVERY_POLICY", "fail"),
+            StaleAfter:          getDurationEnv("SCAN_STALE_AFTER", time.Minute),
+            MaxConcurrent:       getIntEnv("SCAN_MAX_CONCURRENT", 4),
+            MaxPerTarget:        getIntEnv("SCAN_MAX_PER_TARGET", 1),
+            TableConcurrency:    getIntEnv("SCAN_TABLE_CONCURRENCY", 4),
+            TolerateTableErrors: getBoolEnv("SCAN_TOLERATE_TABLE_ERRORS", false),
+        },
+        Scheduler: SchedulerConfig{
+            Enabled:      getBoolEnv("SCHEDULER_ENABLED", true),
+            PollInterval: getDurationEnv("SCHEDULER_POLL_INTERVAL", 15*time.Second),
+        },
+        SQLite: SQLiteConfig{
+            UploadDir:      getStringEnv("SQLITE_UPLOAD_DIR", "data/sqlite"),
+            AllowedDirs:    getListEnv("SQLITE_ALLOWED_DIRS"),
+            MaxUploadBytes: int64(getIntEnv("SQLITE_MAX_UPLOAD_MB", 100)) << 20,
+        },
     }
 
 	if err := cfg.validate(); err != nil {
@@ -107,6 +161,27 @@ func (c *Config) validate() error {
     if c.MetadataDB.Database == "" {
         return fmt.Errorf("METADATA_DB_NAME is required")
     }
+    if c.Scan.RecoveryPolicy != "fail" && c.Scan.RecoveryPolicy != "requeue" {
+        return fmt.Errorf("SCAN_RECOVERY_POLICY must be either fail or requeue")
+    }
+    if minStaleAfter := minStaleAfterHeartbeats * domain.ScanHeartbeatInterval; c.Scan.StaleAfter < minStaleAfter {
+        return fmt.Errorf("SCAN_STALE_AFTER must be at least %s, %d scan heartbeats", minStaleAfter, minStaleAfterHeartbeats)
+    }
+    if c.Scan.MaxConcurrent < 1 {
+        return fmt.Errorf("SCAN_MAX_CONCURRENT must be at least 1")
+    }
+    if c.Scan.MaxPerTarget < 1 {
+        return fmt.Errorf("SCAN_MAX_PER_TARGET must be at least 1")
+    }
+    if c.Scan.TableConcurrency < 1 {
+        return fmt.Errorf("SCAN_TABLE_CONCURRENCY must be at least 1")
+    }
+    if c.Scheduler.PollInterval <= 0 {
+        return fmt.Errorf("SCHEDULER_POLL_INTERVAL must be a positive duration")
+    }
+    if c.SQLite.MaxUploadBytes <= 0 {
+        return fmt.Errorf("SQLITE_MAX_UPLOAD_MB must be at least 1")
+    }
     return nil
 }
 
@@ -126,6 +201,26 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getListEnv splits a comma-separated variable, skipping empty entries.
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
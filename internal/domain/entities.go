@@ -1,6 +1,7 @@
 package domain
 
 import (
+    "errors"
     "time"
 
     "github.com/google/uuid"
@@ -8,6 +9,7 @@ import (
 
 type DatabaseConnection struct {
     ID                uuid.UUID `json:"id"`
+    Engine            Engine    `json:"engine"`
     Host              string    `json:"host" binding:"required"`
     Port              int       `json:"port" binding:"required,min=1,max=65535"`
     Username          string    `json:"username" binding:"required"`
@@ -18,15 +20,95 @@ type DatabaseConnection struct {
     UpdatedAt         time.Time `json:"updated_at"`
     LastScannedAt     *time.Time `json:"last_scanned_at,omitempty"`
     IsActive          bool      `json:"is_active"`
+    SampleSize        int       `json:"sample_size"`
+    FilePath          string    `json:"file_path,omitempty"`
+    ScanScope         *ScanScope `json:"scan_scope,omitempty"`
+    // ScanConcurrency bounds the tables a scan of this connection samples and
+    // classifies at once; 0 uses the server default.
+    ScanConcurrency   int       `json:"scan_concurrency"`
+    // PatternPacks are the installed pattern packs, e.g. es or pt-br, whose
+    // patterns are applied to this connection on top of the core patterns.
+    PatternPacks      []string  `json:"pattern_packs,omitempty"`
+    // RiskPolicyID is the risk policy scans of this connection are scored
+    // with; without one they use the built-in risk calculation.
+    RiskPolicyID      *uuid.UUID `json:"risk_policy_id,omitempty"`
 }
 
+// Engine identifies the database server software of a target connection.
+type Engine string
+
+const (
+	EngineMySQL    Engine = "mysql"
+	EnginePostgres Engine = "postgres"
+	EngineSQLite   Engine = "sqlite"
+)
+
+// CreateDatabaseRequest describes a server connection, or for SQLite a file
+// path on the API host, in which case the network fields are not required.
 type CreateDatabaseRequest struct {
-	Host         string `json:"host" binding:"required"`
-	Port         int    `json:"port" binding:"required,min=1,max=65535"`
-	Username     string `json:"username" binding:"required"`
-	Password     string `json:"password" binding:"required"`
-	DatabaseName string `json:"database_name"`
-	Description  string `json:"description"`
+	Engine          Engine     `json:"engine" binding:"omitempty,oneof=mysql postgres sqlite"`
+	Host            string     `json:"host" binding:"required_unless=Engine sqlite"`
+	Port            int        `json:"port" binding:"required_unless=Engine sqlite,omitempty,min=1,max=65535"`
+	Username        string     `json:"username" binding:"required_unless=Engine sqlite"`
+	Password        string     `json:"password" binding:"required_unless=Engine sqlite"`
+	DatabaseName    string     `json:"database_name"`
+	FilePath        string     `json:"file_path" binding:"required_if=Engine sqlite"`
+	Description     string     `json:"description"`
+	SampleSize      int        `json:"sample_size" binding:"min=0,max=1000"`
+	ScanScope       *ScanScope `json:"scan_scope"`
+	ScanConcurrency int        `json:"scan_concurrency" binding:"min=0,max=32"`
+	PatternPacks    []string   `json:"pattern_packs"`
+	RiskPolicyID    *uuid.UUID `json:"risk_policy_id"`
+}
+
+// ScanScope limits what a scan classifies. Each list holds glob patterns (*
+// and ?, case-insensitive) or, prefixed with "re:", regular expressions matched
+// against the schema, table or column name. When a list of includes is given
+// only matching names are scanned; excludes are applied afterwards.
+type ScanScope struct {
+	// Database is the only schema scanned on MySQL, where schemas are
+	// databases. It is taken from the connection's database_name and only
+	// recorded on scan results.
+	Database       string   `json:"database,omitempty"`
+	IncludeSchemas []string `json:"include_schemas,omitempty"`
+	ExcludeSchemas []string `json:"exclude_schemas,omitempty"`
+	IncludeTables  []string `json:"include_tables,omitempty"`
+	ExcludeTables  []string `json:"exclude_tables,omitempty"`
+	IncludeColumns []string `json:"include_columns,omitempty"`
+	ExcludeColumns []string `json:"exclude_columns,omitempty"`
+}
+
+// StartScanRequest is the optional body of a scan request. Each list set in
+// Scope replaces the same list of the connection's scan scope for this scan.
+// An incremental scan reuses the results of the latest completed scan for the
+// tables that have not changed since.
+type StartScanRequest struct {
+	Scope       *ScanScope `json:"scope"`
+	Incremental bool       `json:"incremental"`
+}
+
+type ScanSchedule struct {
+	ID              uuid.UUID  `json:"id"`
+	DatabaseID      uuid.UUID  `json:"database_id"`
+	CronExpression  string     `json:"cron_expression,omitempty"`
+	IntervalSeconds int        `json:"interval_seconds,omitempty"`
+	Timezone        string     `json:"timezone"`
+	IsPaused        bool       `json:"is_paused"`
+	Incremental     bool       `json:"incremental"`
+	NextRunAt       time.Time  `json:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	LastScanID      *uuid.UUID `json:"last_scan_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateScheduleRequest takes either a standard five-field cron expression or
+// a fixed interval, evaluated in Timezone (UTC when empty).
+type CreateScheduleRequest struct {
+	CronExpression  string `json:"cron_expression"`
+	IntervalSeconds int    `json:"interval_seconds" binding:"omitempty,min=60"`
+	Timezone        string `json:"timezone"`
+	Incremental     bool   `json:"incremental"`
 }
 
 type ScanResult struct {
@@ -34,10 +116,84 @@ type ScanResult struct {
     DatabaseID   uuid.UUID    `json:"database_id"`
     StartedAt    time.Time    `json:"started_at"`
     CompletedAt  *time.Time   `json:"completed_at,omitempty"`
+    HeartbeatAt  *time.Time   `json:"heartbeat_at,omitempty"`
     Status       ScanStatus   `json:"status"`
     ErrorMessage string       `json:"error_message,omitempty"`
     Schemas      []SchemaResult `json:"schemas"`
     Summary      ScanSummary  `json:"summary"`
+    // Scope is the effective scope the scan ran with; nil means everything
+    // the connection can see.
+    Scope        *ScanScope   `json:"scope,omitempty"`
+    // ScopeOverridden is set when the scan request replaced the connection's
+    // scope. Such scans are never taken as the latest scan of the database.
+    ScopeOverridden bool      `json:"scope_overridden,omitempty"`
+    Progress     *ScanProgress `json:"progress,omitempty"`
+    Incremental  bool         `json:"incremental"`
+    // BaseScanID is the completed scan an incremental scan reused unchanged
+    // tables from.
+    BaseScanID   *uuid.UUID   `json:"base_scan_id,omitempty"`
+    // Errors lists the objects a scan tolerating table errors could not read.
+    Errors       []ScanError  `json:"errors,omitempty"`
+    QueuePosition *int        `json:"queue_position,omitempty"`
+}
+
+// ScanProgress reports how far a running scan has got. The totals are known
+// once the catalog of every schema in scope has been read. Tables are
+// classified concurrently, so CurrentTable is the one most recently started.
+type ScanProgress struct {
+	SchemasTotal     int       `json:"schemas_total"`
+	SchemasProcessed int       `json:"schemas_processed"`
+	TablesTotal      int       `json:"tables_total"`
+	TablesProcessed  int       `json:"tables_processed"`
+	CurrentSchema    string    `json:"current_schema,omitempty"`
+	CurrentTable     string    `json:"current_table,omitempty"`
+	// ETASeconds extrapolates the time per table so far to the remaining
+	// tables; it is omitted until the first table is done.
+	ETASeconds *int64    `json:"eta_seconds,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ScanPhase names the step of a scan an error occurred in.
+type ScanPhase string
+
+const (
+	// ScanPhaseTables is reading the tables of a schema; the schema is skipped.
+	ScanPhaseTables ScanPhase = "tables"
+	// ScanPhaseColumns is reading the columns of a table; the table is skipped.
+	ScanPhaseColumns ScanPhase = "columns"
+	// ScanPhaseSample is sampling the values of a table, which is then
+	// classified by column names only.
+	ScanPhaseSample ScanPhase = "sample"
+)
+
+// ScanError is a schema- or table-level failure recorded by a scan that
+// tolerates them instead of failing.
+type ScanError struct {
+	SchemaName string    `json:"schema_name"`
+	TableName  string    `json:"table_name,omitempty"`
+	Phase      ScanPhase `json:"phase"`
+	Message    string    `json:"message"`
+}
+
+// ScanEventType names the events streamed while watching a scan.
+type ScanEventType string
+
+const (
+	ScanEventProgress ScanEventType = "progress"
+	ScanEventTable    ScanEventType = "table"
+	ScanEventDone     ScanEventType = "done"
+)
+
+// ScanEvent is one update of a watched scan: its progress, the findings of a
+// table that was just classified, or the final status once the scan is over.
+type ScanEvent struct {
+	Type         ScanEventType `json:"type"`
+	ScanID       uuid.UUID     `json:"scan_id"`
+	Progress     *ScanProgress `json:"progress,omitempty"`
+	SchemaName   string        `json:"schema_name,omitempty"`
+	Table        *TableResult  `json:"table,omitempty"`
+	Status       ScanStatus    `json:"status,omitempty"`
+	ErrorMessage string        `json:"error_message,omitempty"`
 }
 
 type ScanStatus string
@@ -48,16 +204,177 @@ const (
 	ScanStatusCompleted ScanStatus = "completed"
 	ScanStatusFailed    ScanStatus = "failed"
 	ScanStatusCancelled ScanStatus = "cancelled"
+
+	// ScanStatusCompletedWithErrors is a scan that finished but skipped the
+	// objects listed in its errors.
+	ScanStatusCompletedWithErrors ScanStatus = "completed_with_errors"
+)
+
+// ScanHeartbeatInterval is how often a running scan records a heartbeat and
+// re-reads its status so that a cancellation accepted by another API replica
+// stops it too.
+const ScanHeartbeatInterval = 5 * time.Second
+
+// ErrScanInProgress is returned when a scan is requested for a database that
+// already has one pending or running.
+var ErrScanInProgress = errors.New("a scan is already pending or running for this database")
+
+// ErrInvalidScanScope is returned when a scan scope holds a pattern that does
+// not compile.
+var ErrInvalidScanScope = errors.New("invalid scan scope")
+
+// ErrInvalidFindingFilter is returned when a findings search holds a filter
+// value out of range or unknown.
+var ErrInvalidFindingFilter = errors.New("invalid finding filter")
+
+// ErrScanNotFound is returned for a scan result that does not exist.
+var ErrScanNotFound = errors.New("scan result not found")
+
+// ErrInvalidScanDiff is returned when the scans asked to be diffed cannot be
+// compared, such as a "from" scan that did not start before the "to" scan.
+var ErrInvalidScanDiff = errors.New("invalid scan diff")
+
+// ErrInvalidSQLiteFile is returned when an uploaded file cannot be opened as a
+// SQLite database.
+var ErrInvalidSQLiteFile = errors.New("file is not a SQLite database")
+
+// ErrPatternPackNotFound is returned for a pattern pack that has no file in
+// the packs directory.
+var ErrPatternPackNotFound = errors.New("pattern pack not found")
+
+// ScanJob is the queue entry for a pending or running scan. Target identifies
+// the scanned server so that concurrency can be limited per server.
+type ScanJob struct {
+	ScanID     uuid.UUID  `json:"scan_id"`
+	DatabaseID uuid.UUID  `json:"database_id"`
+	Target     string     `json:"target"`
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+	ClaimedBy  string     `json:"claimed_by,omitempty"`
+	ClaimedAt  *time.Time `json:"claimed_at,omitempty"`
+}
+
+type RecoveryPolicy string
+
+const (
+	RecoveryPolicyFail    RecoveryPolicy = "fail"
+	RecoveryPolicyRequeue RecoveryPolicy = "requeue"
 )
 
+type RecoveryReport struct {
+	Policy   RecoveryPolicy `json:"policy"`
+	Found    int            `json:"found"`
+	Failed   int            `json:"failed"`
+	Requeued int            `json:"requeued"`
+}
+
+// ScanDiff describes what changed between two completed scans of the same
+// database. Columns of added or removed tables are listed individually in
+// AddedColumns and RemovedColumns so alerting only has to look there.
+type ScanDiff struct {
+	DatabaseID      uuid.UUID        `json:"database_id"`
+	FromScanID      uuid.UUID        `json:"from_scan_id"`
+	ToScanID        uuid.UUID        `json:"to_scan_id"`
+	FromCompletedAt *time.Time       `json:"from_completed_at,omitempty"`
+	ToCompletedAt   *time.Time       `json:"to_completed_at,omitempty"`
+	AddedSchemas    []string         `json:"added_schemas"`
+	RemovedSchemas  []string         `json:"removed_schemas"`
+	AddedTables     []TableRef       `json:"added_tables"`
+	RemovedTables   []TableRef       `json:"removed_tables"`
+	AddedColumns    []ColumnChange   `json:"added_columns"`
+	RemovedColumns  []ColumnChange   `json:"removed_columns"`
+	ChangedColumns  []ColumnChange   `json:"changed_columns"`
+	RiskLevelChange *RiskLevelChange `json:"risk_level_change,omitempty"`
+	// NewlyExposedColumns counts columns that hold sensitive information in
+	// the newer scan but did not in the older one, whether added or changed.
+	NewlyExposedColumns int  `json:"newly_exposed_columns"`
+	HasChanges          bool `json:"has_changes"`
+}
+
+type TableRef struct {
+	SchemaName string `json:"schema_name"`
+	TableName  string `json:"table_name"`
+}
+
+type ColumnChange struct {
+	SchemaName          string          `json:"schema_name"`
+	TableName           string          `json:"table_name"`
+	ColumnName          string          `json:"column_name"`
+	FromInformationType InformationType `json:"from_information_type,omitempty"`
+	ToInformationType   InformationType `json:"to_information_type,omitempty"`
+	FromConfidenceScore float64         `json:"from_confidence_score,omitempty"`
+	ToConfidenceScore   float64         `json:"to_confidence_score,omitempty"`
+}
+
+type RiskLevelChange struct {
+	From RiskLevel `json:"from"`
+	To   RiskLevel `json:"to"`
+}
+
+// Finding is a column from the latest completed scan of a database, as
+// returned by the cross-database findings search.
+type Finding struct {
+	DatabaseID      uuid.UUID       `json:"database_id"`
+	Host            string          `json:"host"`
+	Port            int             `json:"port"`
+	DatabaseName    string          `json:"database_name,omitempty"`
+	ScanID          uuid.UUID       `json:"scan_id"`
+	ScannedAt       *time.Time      `json:"scanned_at,omitempty"`
+	RiskLevel       RiskLevel       `json:"risk_level"`
+	SchemaName      string          `json:"schema_name"`
+	TableName       string          `json:"table_name"`
+	ColumnName      string          `json:"column_name"`
+	DataType        string          `json:"data_type"`
+	InformationType InformationType `json:"information_type"`
+	ConfidenceScore float64         `json:"confidence_score"`
+	MatchedPatterns []string        `json:"matched_patterns"`
+}
+
+// FindingFilter narrows a findings search. Empty fields do not filter; Schema,
+// Table and Column accept * and ? wildcards; RiskLevels matches the risk level
+// of the scan the finding belongs to.
+type FindingFilter struct {
+	InformationTypes []InformationType
+	MinConfidence    float64
+	Schema           string
+	Table            string
+	Column           string
+	RiskLevels       []RiskLevel
+	DataTypes        []string
+	Limit            int
+	Offset           int
+}
+
 type SchemaResult struct {
     SchemaName string        `json:"schema_name"`
     Tables     []TableResult `json:"tables"`
+    // RiskLevel, SensitiveColumns and DominantInformationTypes summarize the
+    // tables of the schema as TableResult does its columns.
+    RiskScore                float64           `json:"risk_score,omitempty"`
+    RiskLevel                RiskLevel         `json:"risk_level,omitempty"`
+    SensitiveColumns         int               `json:"sensitive_columns"`
+    DominantInformationTypes []InformationType `json:"dominant_information_types,omitempty"`
 }
 
 type TableResult struct {
     TableName string         `json:"table_name"`
     Columns   []ColumnResult `json:"columns"`
+    // Fingerprint and DataVersion identify the columns and data the table
+    // was classified with; an incremental scan reuses the table while both
+    // are unchanged.
+    Fingerprint string `json:"fingerprint,omitempty"`
+    DataVersion string `json:"data_version,omitempty"`
+    // Reused marks a table copied from the base scan of an incremental scan
+    // rather than sampled and classified again.
+    Reused bool `json:"reused"`
+    // RiskLevel is the risk of the table alone, computed like that of the
+    // scan; RiskScore is only set when the scan was scored with a risk
+    // policy. SensitiveColumns counts the columns of high or medium
+    // sensitivity and DominantInformationTypes lists the most frequent
+    // information types among the classified columns.
+    RiskScore                float64           `json:"risk_score,omitempty"`
+    RiskLevel                RiskLevel         `json:"risk_level,omitempty"`
+    SensitiveColumns         int               `json:"sensitive_columns"`
+    DominantInformationTypes []InformationType `json:"dominant_information_types,omitempty"`
 }
 
 type ColumnResult struct {
@@ -68,8 +385,50 @@ type ColumnResult struct {
     MatchedPatterns []string        `json:"matched_patterns"`
     IsNullable      bool            `json:"is_nullable"`
     DefaultValue    *string         `json:"default_value,omitempty"`
+    SampleSize      int             `json:"sample_size,omitempty"`
+    MatchedSamples  int             `json:"matched_samples,omitempty"`
+    // ContextRules explains the matched patterns whose table or co-column
+    // conditions held for this column.
+    ContextRules    []ContextRuleMatch `json:"context_rules,omitempty"`
+    MaxLength       *int64          `json:"max_length,omitempty"`
+    Comment         string          `json:"comment,omitempty"`
+}
+
+// ColumnContext is what the classifier knows about a column: its name and
+// the table it lives in.
+type ColumnContext struct {
+	SchemaName string `json:"schema_name"`
+	TableName  string `json:"table_name"`
+	ColumnName string `json:"column_name"`
+	DataType   string `json:"data_type"`
+	ColumnKey  string `json:"column_key"`
+	MaxLength  *int64 `json:"max_length,omitempty"`
+	// Comment and TableComment are the catalog comments of the column and
+	// its table; empty when there are none.
+	Comment      string `json:"comment,omitempty"`
+	TableComment string `json:"table_comment,omitempty"`
+	// SiblingColumns are the columns of the table; the column itself is
+	// ignored among them.
+	SiblingColumns []string `json:"sibling_columns"`
+	// Packs are the pattern packs enabled for the column's connection;
+	// patterns of other packs are ignored.
+	Packs []string `json:"packs,omitempty"`
+}
+
+// ContextRuleMatch records that the context conditions of a pattern held for
+// a column and how they changed its confidence score.
+type ContextRuleMatch struct {
+	Pattern      string `json:"pattern"`
+	TablePattern string `json:"table_pattern,omitempty"`
+	// CoColumns are the sibling columns that satisfied the pattern's
+	// co-column conditions, one per condition.
+	CoColumns []string `json:"co_columns,omitempty"`
+	Boost     float64  `json:"boost"`
 }
 
+// InformationType names an entry of the information type catalog. The
+// constants are the built-in types the value detectors report; others are
+// defined through the catalog.
 type InformationType string
 
 const (
@@ -102,10 +461,26 @@ type ScanSummary struct {
     InformationTypesCounts map[InformationType]int `json:"information_types_counts"`
     RiskLevel              RiskLevel               `json:"risk_level"`
     DurationMilliseconds   int64                   `json:"duration_milliseconds"`
+    SkippedSchemas         int                     `json:"skipped_schemas,omitempty"`
+    SkippedTables          int                     `json:"skipped_tables,omitempty"`
+    ReusedTables           int                     `json:"reused_tables,omitempty"`
+    // RiskScore, RiskPolicyID and RiskPolicy are set when RiskLevel comes
+    // from a risk policy rather than the built-in calculation.
+    RiskScore              float64                 `json:"risk_score,omitempty"`
+    RiskPolicyID           *uuid.UUID              `json:"risk_policy_id,omitempty"`
+    RiskPolicy             string                  `json:"risk_policy,omitempty"`
 }
 
 type RiskLevel string
 
+// ClassificationSort orders the schemas and tables of a classification. The
+// empty sort keeps the order they were scanned in.
+type ClassificationSort string
+
+const (
+	ClassificationSortRisk ClassificationSort = "risk"
+)
+
 const (
 	RiskLevelLow      RiskLevel = "low"
 	RiskLevelMedium   RiskLevel = "medium"
@@ -113,34 +488,277 @@ const (
 	RiskLevelCritical RiskLevel = "critical"
 )
 
+// SensitivityTier ranks information types for the risk level of a scan: any
+// high-tier column makes a scan high risk, medium-tier columns raise it to
+// medium, and low-tier columns do not count as sensitive.
+type SensitivityTier string
+
+const (
+	SensitivityHigh   SensitivityTier = "high"
+	SensitivityMedium SensitivityTier = "medium"
+	SensitivityLow    SensitivityTier = "low"
+)
+
+// InformationTypeDefinition is an entry of the information type catalog that
+// patterns classify columns into.
+type InformationTypeDefinition struct {
+	Name        InformationType `json:"name"`
+	Description string          `json:"description"`
+	Sensitivity SensitivityTier `json:"sensitivity"`
+	// Parent is the category the type belongs to, itself a catalog entry,
+	// e.g. GOVERNMENT_ID for NATIONAL_ID; empty for top-level types.
+	Parent InformationType `json:"parent,omitempty"`
+	// RegulatoryTags name the regulations that cover the type, e.g. GDPR or
+	// PCI-DSS.
+	RegulatoryTags []string  `json:"regulatory_tags,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CreateInformationTypeRequest struct {
+	Name           InformationType `json:"name" binding:"required"`
+	Description    string          `json:"description" binding:"required"`
+	Sensitivity    SensitivityTier `json:"sensitivity" binding:"required,oneof=high medium low"`
+	Parent         InformationType `json:"parent"`
+	RegulatoryTags []string        `json:"regulatory_tags"`
+}
+
+// RiskAggregation combines scores into the score of what contains them: the
+// columns of a table, or the tables of a schema or database.
+type RiskAggregation string
+
+const (
+	RiskAggregationMax     RiskAggregation = "max"
+	RiskAggregationSum     RiskAggregation = "sum"
+	RiskAggregationAverage RiskAggregation = "average"
+)
+
+// RiskThresholds are the lowest scores of each risk level; lower scores are
+// low risk.
+type RiskThresholds struct {
+	Medium   float64 `json:"medium"`
+	High     float64 `json:"high"`
+	Critical float64 `json:"critical"`
+}
+
+// RiskPolicy scores the risk of a scan. Every classified column scores the
+// weight of its information type, or failing that of the type's sensitivity
+// tier; columns classified with less than MinConfidence score 0. Column
+// scores are aggregated into table scores and those into the database score,
+// and each score is given the level of the highest threshold it reaches.
+type RiskPolicy struct {
+	ID                  uuid.UUID                   `json:"id"`
+	Name                string                      `json:"name"`
+	Description         string                      `json:"description"`
+	TypeWeights         map[InformationType]float64 `json:"type_weights,omitempty"`
+	TierWeights         map[SensitivityTier]float64 `json:"tier_weights,omitempty"`
+	MinConfidence       float64                     `json:"min_confidence"`
+	TableAggregation    RiskAggregation             `json:"table_aggregation"`
+	DatabaseAggregation RiskAggregation             `json:"database_aggregation"`
+	Thresholds          RiskThresholds              `json:"thresholds"`
+	CreatedAt           time.Time                   `json:"created_at"`
+	UpdatedAt           time.Time                   `json:"updated_at"`
+}
+
+type CreateRiskPolicyRequest struct {
+	Name                string                      `json:"name" binding:"required"`
+	Description         string                      `json:"description"`
+	TypeWeights         map[InformationType]float64 `json:"type_weights"`
+	TierWeights         map[SensitivityTier]float64 `json:"tier_weights"`
+	MinConfidence       float64                     `json:"min_confidence" binding:"min=0,max=1"`
+	TableAggregation    RiskAggregation             `json:"table_aggregation" binding:"required,oneof=max sum average"`
+	DatabaseAggregation RiskAggregation             `json:"database_aggregation" binding:"required,oneof=max sum average"`
+	Thresholds          RiskThresholds              `json:"thresholds"`
+}
+
+// RecomputeRiskRequest scores the latest completed scans of a database again
+// under PolicyID, or the connection's policy when it is not given. Unless
+// Apply is set the new scores are only reported, not stored.
+type RecomputeRiskRequest struct {
+	PolicyID *uuid.UUID `json:"policy_id"`
+	Limit    int        `json:"limit" binding:"min=0,max=100"`
+	Apply    bool       `json:"apply"`
+}
+
+// RiskRecomputation compares the risk of a scan as stored with its risk under
+// the policy of a RecomputeRiskRequest.
+type RiskRecomputation struct {
+	ScanID            uuid.UUID `json:"scan_id"`
+	StartedAt         time.Time `json:"started_at"`
+	PreviousRiskLevel RiskLevel `json:"previous_risk_level"`
+	PreviousRiskScore float64   `json:"previous_risk_score,omitempty"`
+	PreviousPolicy    string    `json:"previous_policy,omitempty"`
+	RiskLevel         RiskLevel `json:"risk_level"`
+	RiskScore         float64   `json:"risk_score"`
+	Applied           bool      `json:"applied"`
+}
+
+// PatternTarget is the text of a column a pattern is matched against.
+type PatternTarget string
+
+const (
+	PatternTargetName    PatternTarget = "name"
+	PatternTargetComment PatternTarget = "comment"
+	PatternTargetAny     PatternTarget = "any"
+)
+
 type ClassificationPattern struct {
     ID              uuid.UUID        `json:"id"`
     InformationType InformationType  `json:"information_type"`
     Pattern         string           `json:"pattern"`
     Description     string           `json:"description"`
     Priority        int              `json:"priority"`
+    Validator       string           `json:"validator,omitempty"`
+    // Target selects whether Pattern matches the column name, its comment or
+    // either; empty means the name.
+    Target          PatternTarget    `json:"target,omitempty"`
+    // MatchTokens also matches Pattern against the column name split into
+    // tokens with its abbreviations expanded, e.g. cust_first_nm as
+    // customer_first_name and as any run of its tokens ending at the last
+    // one, such as first_name.
+    MatchTokens     bool             `json:"match_tokens,omitempty"`
+    // Pack is the pattern pack that installed the pattern, e.g. es or pt-br;
+    // empty for core patterns, which apply to every connection.
+    Pack            string           `json:"pack,omitempty"`
+    // TablePattern and CoColumns are regular expressions a column's table
+    // name or comment and sibling columns must match for the pattern's
+    // context to hold.
+    // With a zero ContextBoost the pattern only applies where its context
+    // holds; otherwise it always applies and ContextBoost is added to its
+    // score where the context holds.
+    TablePattern    string           `json:"table_pattern,omitempty"`
+    CoColumns       []string         `json:"co_columns,omitempty"`
+    ContextBoost    float64          `json:"context_boost,omitempty"`
+    // AllowedDataTypes and DisallowedDataTypes constrain the data types a
+    // matching column is expected to have, and MinLength and MaxLength its
+    // declared length. Columns that satisfy them score higher, the others
+    // lower; types are compared case-insensitively.
+    AllowedDataTypes    []string     `json:"allowed_data_types,omitempty"`
+    DisallowedDataTypes []string     `json:"disallowed_data_types,omitempty"`
+    MinLength       *int             `json:"min_length,omitempty"`
+    MaxLength       *int             `json:"max_length,omitempty"`
     IsActive        bool             `json:"is_active"`
     CreatedAt       time.Time        `json:"created_at"`
     UpdatedAt       time.Time        `json:"updated_at"`
 }
 
 type CreatePatternRequest struct {
-	InformationType InformationType `json:"information_type" binding:"required"`
-	Pattern         string          `json:"pattern" binding:"required"`
-	Description     string          `json:"description" binding:"required"`
-	Priority        int             `json:"priority" binding:"min=1,max=100"`
+	InformationType     InformationType `json:"information_type" binding:"required"`
+	Pattern             string          `json:"pattern" binding:"required"`
+	Description         string          `json:"description" binding:"required"`
+	Priority            int             `json:"priority" binding:"min=1,max=100"`
+	Validator           string          `json:"validator"`
+	Target              PatternTarget   `json:"target" binding:"omitempty,oneof=name comment any"`
+	MatchTokens         bool            `json:"match_tokens"`
+	Pack                string          `json:"pack"`
+	TablePattern        string          `json:"table_pattern"`
+	CoColumns           []string        `json:"co_columns"`
+	ContextBoost        float64         `json:"context_boost" binding:"min=-1,max=1"`
+	AllowedDataTypes    []string        `json:"allowed_data_types"`
+	DisallowedDataTypes []string        `json:"disallowed_data_types"`
+	MinLength           *int            `json:"min_length" binding:"omitempty,min=0"`
+	MaxLength           *int            `json:"max_length" binding:"omitempty,min=1"`
+}
+
+// TestPatternsRequest tries patterns on column names without storing them.
+// With Pattern set the candidate is tested alone, or along with the active
+// patterns when IncludeActive is set; without it the active patterns are
+// tested.
+type TestPatternsRequest struct {
+	Pattern       *CreatePatternRequest `json:"pattern"`
+	IncludeActive bool                  `json:"include_active"`
+	Columns       []string              `json:"columns" binding:"required,min=1,max=1000,dive,required"`
+	// TableName and DataType apply to every column, for context conditions
+	// and type constraints; the columns are each other's siblings.
+	TableName string `json:"table_name"`
+	DataType  string `json:"data_type"`
+	// Packs are enabled as for a connection; the pack of the candidate is
+	// always enabled.
+	Packs []string `json:"packs"`
+}
+
+// ColumnExplanation is the classification of a column with every pattern
+// that matched it, best first.
+type ColumnExplanation struct {
+	ColumnName      string                    `json:"column_name"`
+	InformationType InformationType           `json:"information_type"`
+	ConfidenceScore float64                   `json:"confidence_score"`
+	Matches         []PatternMatchExplanation `json:"matches"`
+	ContextRules    []ContextRuleMatch        `json:"context_rules,omitempty"`
+}
+
+// PatternMatchExplanation is a pattern that matched a column. MatchedPattern
+// is the entry it adds to matched_patterns, prefixed with tokens: or comment:
+// when it matched those, and MatchedText the text it matched.
+type PatternMatchExplanation struct {
+	Pattern         string              `json:"pattern"`
+	InformationType InformationType     `json:"information_type"`
+	Priority        int                 `json:"priority"`
+	Pack            string              `json:"pack,omitempty"`
+	MatchedPattern  string              `json:"matched_pattern"`
+	MatchedText     string              `json:"matched_text"`
+	ConfidenceScore float64             `json:"confidence_score"`
+	Breakdown       ConfidenceBreakdown `json:"breakdown"`
+}
+
+// ConfidenceBreakdown is how the confidence score of a pattern match is made
+// up: the priority of the pattern over 100, plus the exact-match bonus, minus
+// the common-word penalty, plus the type adjustment, clamped to [0, 1], then
+// minus the token penalty and plus the context boost, clamped again.
+type ConfidenceBreakdown struct {
+	BasePriority      float64 `json:"base_priority"`
+	ExactMatchBonus   float64 `json:"exact_match_bonus"`
+	CommonWordPenalty float64 `json:"common_word_penalty"`
+	TypeAdjustment    float64 `json:"type_adjustment"`
+	TokenPenalty      float64 `json:"token_penalty"`
+	ContextBoost      float64 `json:"context_boost"`
+}
+
+// Abbreviation expands a token of column names, e.g. nm to name or dob to
+// date_of_birth, for patterns that match tokens.
+type Abbreviation struct {
+	ID           uuid.UUID `json:"id"`
+	Abbreviation string    `json:"abbreviation"`
+	Expansion    string    `json:"expansion"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type CreateAbbreviationRequest struct {
+	Abbreviation string `json:"abbreviation" binding:"required"`
+	Expansion    string `json:"expansion" binding:"required"`
+}
+
+// PatternPack is a set of patterns for a locale, such as es, or a country,
+// such as pt-br, that can be installed into the pattern table and enabled
+// per connection.
+type PatternPack struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Patterns    int    `json:"patterns"`
+	// Installed is the number of the pack's patterns in the pattern table.
+	Installed int `json:"installed"`
 }
 
-type MySQLTableInfo struct {
-    SchemaName string            `json:"schema_name"`
-    TableName  string            `json:"table_name"`
-    Columns    []MySQLColumnInfo `json:"columns"`
+type TableInfo struct {
+    SchemaName string       `json:"schema_name"`
+    TableName  string       `json:"table_name"`
+    Comment    string       `json:"comment,omitempty"`
+    Columns    []ColumnInfo `json:"columns"`
+    // DataVersion changes whenever the table's data may have changed; it is
+    // empty when the engine cannot tell, and such tables are never reused.
+    DataVersion string `json:"data_version,omitempty"`
 }
 
-type MySQLColumnInfo struct {
+type ColumnInfo struct {
 	ColumnName   string  `json:"column_name"`
 	DataType     string  `json:"data_type"`
 	IsNullable   bool    `json:"is_nullable"`
 	DefaultValue *string `json:"default_value"`
 	ColumnKey    string  `json:"column_key"`
+	// MaxLength is the declared maximum length of character columns, as in
+	// CHARACTER_MAXIMUM_LENGTH; nil for other types or unbounded columns.
+	MaxLength *int64 `json:"max_length,omitempty"`
+	// Comment is the column's comment in the catalog, e.g. COLUMN_COMMENT.
+	Comment string `json:"comment,omitempty"`
 }
@@ -25,9 +25,42 @@ type ScanResultRepository interface {
     Update(ctx context.Context, result *ScanResult) error
     Delete(ctx context.Context, id uuid.UUID) error
     UpdateStatus(ctx context.Context, id uuid.UUID, status ScanStatus, errorMessage string) error
+    UpdateIfStatus(ctx context.Context, result *ScanResult, expected ScanStatus) (bool, error)
+    TransitionStatus(ctx context.Context, id uuid.UUID, status ScanStatus, errorMessage string, from ...ScanStatus) (bool, error)
+    GetStatus(ctx context.Context, id uuid.UUID) (ScanStatus, error)
+    Heartbeat(ctx context.Context, id uuid.UUID) error
+    UpdateProgress(ctx context.Context, id uuid.UUID, progress *ScanProgress) error
+    UpdateRisk(ctx context.Context, result *ScanResult) error
+    ReclaimStale(ctx context.Context, id uuid.UUID, staleBefore time.Time, status ScanStatus, errorMessage string) (bool, error)
     GetRunningScans(ctx context.Context) ([]*ScanResult, error)
 }
 
+type ScanJobRepository interface {
+    Enqueue(ctx context.Context, job *ScanJob) error
+    Requeue(ctx context.Context, job *ScanJob) error
+    Claim(ctx context.Context, workerID string, maxConcurrent, maxPerTarget int) (*ScanJob, error)
+    GetAll(ctx context.Context) ([]*ScanJob, error)
+    GetQueuePosition(ctx context.Context, scanID uuid.UUID) (int, error)
+    Delete(ctx context.Context, scanID uuid.UUID) error
+}
+
+type ScanScheduleRepository interface {
+    Create(ctx context.Context, schedule *ScanSchedule) error
+    GetByID(ctx context.Context, id uuid.UUID) (*ScanSchedule, error)
+    GetAll(ctx context.Context) ([]*ScanSchedule, error)
+    GetByDatabaseID(ctx context.Context, databaseID uuid.UUID) ([]*ScanSchedule, error)
+    GetDue(ctx context.Context, now time.Time, limit int) ([]*ScanSchedule, error)
+    Update(ctx context.Context, schedule *ScanSchedule) error
+    MarkFired(ctx context.Context, id uuid.UUID, expectedNextRunAt, nextRunAt, firedAt time.Time) (bool, error)
+    SetLastScan(ctx context.Context, id uuid.UUID, scanID uuid.UUID) error
+    Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type LeaderLeaseRepository interface {
+    TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+    Release(ctx context.Context, name, holder string) error
+}
+
 type ClassificationPatternRepository interface {
     Create(ctx context.Context, pattern *ClassificationPattern) error
     GetByID(ctx context.Context, id uuid.UUID) (*ClassificationPattern, error)
@@ -37,4 +70,35 @@ type ClassificationPatternRepository interface {
     Update(ctx context.Context, pattern *ClassificationPattern) error
     Delete(ctx context.Context, id uuid.UUID) error
     ExistsByPattern(ctx context.Context, pattern string) (bool, error)
+    CountByPack(ctx context.Context) (map[string]int, error)
+    DeleteByPack(ctx context.Context, pack string) (int, error)
+}
+
+type AbbreviationRepository interface {
+    Create(ctx context.Context, abbreviation *Abbreviation) error
+    GetByID(ctx context.Context, id uuid.UUID) (*Abbreviation, error)
+    GetAll(ctx context.Context) ([]*Abbreviation, error)
+    Update(ctx context.Context, abbreviation *Abbreviation) error
+    Delete(ctx context.Context, id uuid.UUID) error
+    ExistsByAbbreviation(ctx context.Context, abbreviation string) (bool, error)
+}
+
+type InformationTypeRepository interface {
+    Create(ctx context.Context, infoType *InformationTypeDefinition) error
+    GetByName(ctx context.Context, name InformationType) (*InformationTypeDefinition, error)
+    GetAll(ctx context.Context) ([]*InformationTypeDefinition, error)
+    Update(ctx context.Context, infoType *InformationTypeDefinition) error
+    Delete(ctx context.Context, name InformationType) error
+}
+
+type RiskPolicyRepository interface {
+    Create(ctx context.Context, policy *RiskPolicy) error
+    GetByID(ctx context.Context, id uuid.UUID) (*RiskPolicy, error)
+    GetAll(ctx context.Context) ([]*RiskPolicy, error)
+    Update(ctx context.Context, policy *RiskPolicy) error
+    Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type FindingRepository interface {
+    Search(ctx context.Context, filter FindingFilter) ([]*Finding, int, error)
 }
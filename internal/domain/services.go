@@ -2,6 +2,8 @@ package domain
 
 import (
     "context"
+    "io"
+    "time"
 
     "github.com/google/uuid"
 )
@@ -13,14 +15,40 @@ type DatabaseService interface {
     UpdateConnection(ctx context.Context, id uuid.UUID, req *CreateDatabaseRequest) error
     DeleteConnection(ctx context.Context, id uuid.UUID) error
     TestConnection(ctx context.Context, id uuid.UUID) error
+    ImportSQLiteFile(ctx context.Context, file io.Reader, description string, sampleSize int) (uuid.UUID, error)
 }
 
 type ScanService interface {
-    StartScan(ctx context.Context, databaseID uuid.UUID) (uuid.UUID, error)
+    StartScan(ctx context.Context, databaseID uuid.UUID, req StartScanRequest) (uuid.UUID, error)
     GetScanResult(ctx context.Context, scanID uuid.UUID) (*ScanResult, error)
     GetScanHistory(ctx context.Context, databaseID uuid.UUID, limit int) ([]*ScanResult, error)
-    GetLatestClassification(ctx context.Context, databaseID uuid.UUID) (*ScanResult, error)
+    GetLatestClassification(ctx context.Context, databaseID uuid.UUID, sortBy ClassificationSort) (*ScanResult, error)
+    DiffScans(ctx context.Context, databaseID uuid.UUID, fromScanID, toScanID *uuid.UUID) (*ScanDiff, error)
     CancelScan(ctx context.Context, scanID uuid.UUID) error
+    WatchScan(ctx context.Context, scanID uuid.UUID) (<-chan ScanEvent, error)
+    RecoverOrphanedScans(ctx context.Context, policy RecoveryPolicy, staleAfter time.Duration) (*RecoveryReport, error)
+}
+
+type ScheduleService interface {
+    CreateSchedule(ctx context.Context, databaseID uuid.UUID, req *CreateScheduleRequest) (*ScanSchedule, error)
+    GetSchedules(ctx context.Context, databaseID uuid.UUID) ([]*ScanSchedule, error)
+    GetAllSchedules(ctx context.Context) ([]*ScanSchedule, error)
+    PauseSchedule(ctx context.Context, id uuid.UUID) error
+    ResumeSchedule(ctx context.Context, id uuid.UUID) error
+    DeleteSchedule(ctx context.Context, id uuid.UUID) error
+}
+
+type FindingService interface {
+    SearchFindings(ctx context.Context, filter *FindingFilter) ([]*Finding, int, error)
+}
+
+type RiskService interface {
+    CreatePolicy(ctx context.Context, req *CreateRiskPolicyRequest) (uuid.UUID, error)
+    GetPolicy(ctx context.Context, id uuid.UUID) (*RiskPolicy, error)
+    GetAllPolicies(ctx context.Context) ([]*RiskPolicy, error)
+    UpdatePolicy(ctx context.Context, id uuid.UUID, req *CreateRiskPolicyRequest) error
+    DeletePolicy(ctx context.Context, id uuid.UUID) error
+    RecomputeRisk(ctx context.Context, databaseID uuid.UUID, req *RecomputeRiskRequest) ([]*RiskRecomputation, error)
 }
 
 type ClassificationService interface {
@@ -29,13 +57,38 @@ type ClassificationService interface {
     GetAllPatterns(ctx context.Context) ([]*ClassificationPattern, error)
     UpdatePattern(ctx context.Context, id uuid.UUID, req *CreatePatternRequest) error
     DeletePattern(ctx context.Context, id uuid.UUID) error
-    ClassifyColumn(columnName string) (InformationType, float64, []string)
+    TestPatterns(ctx context.Context, req *TestPatternsRequest) ([]*ColumnExplanation, error)
+    CreateAbbreviation(ctx context.Context, req *CreateAbbreviationRequest) (uuid.UUID, error)
+    GetAllAbbreviations(ctx context.Context) ([]*Abbreviation, error)
+    UpdateAbbreviation(ctx context.Context, id uuid.UUID, req *CreateAbbreviationRequest) error
+    DeleteAbbreviation(ctx context.Context, id uuid.UUID) error
+    CreateInformationType(ctx context.Context, req *CreateInformationTypeRequest) error
+    GetInformationType(ctx context.Context, name InformationType) (*InformationTypeDefinition, error)
+    GetAllInformationTypes(ctx context.Context) ([]*InformationTypeDefinition, error)
+    UpdateInformationType(ctx context.Context, name InformationType, req *CreateInformationTypeRequest) error
+    DeleteInformationType(ctx context.Context, name InformationType) error
+    Sensitivity(infoType InformationType) SensitivityTier
+    ListPatternPacks(ctx context.Context) ([]*PatternPack, error)
+    InstallPatternPack(ctx context.Context, name string) (int, error)
+    UninstallPatternPack(ctx context.Context, name string) (int, error)
+    ClassifyColumn(column ColumnContext) (InformationType, float64, []string, []ContextRuleMatch)
+    ClassifyValues(dataType string, values []string) (InformationType, float64, int, string)
+    ValidateValues(pattern string, values []string) (passed int, total int, ok bool)
+    ConfigVersion() string
 }
 
-type MySQLInspector interface {
-	Connect(host string, port int, username, password string) error
-	GetSchemas() ([]string, error)
-	GetTables(schema string) ([]string, error)
-	GetTableInfo(schema, table string) (*MySQLTableInfo, error)
+// Inspector reads the catalog and samples the data of a target database. Each
+// Engine has its own implementation. For SQLite, database is the file path and
+// the network arguments are ignored.
+type Inspector interface {
+	Connect(ctx context.Context, host string, port int, username, password, database string) error
+	TestConnection(ctx context.Context, host string, port int, username, password, database string) error
+	GetSchemas(ctx context.Context) ([]string, error)
+	GetTables(ctx context.Context, schema string) ([]string, error)
+	GetTableInfo(ctx context.Context, schema, table string) (*TableInfo, error)
+	// GetSchemaTables returns every table of schema, as GetTables lists them,
+	// with its columns, reading the catalog once for the whole schema.
+	GetSchemaTables(ctx context.Context, schema string) ([]*TableInfo, error)
+	SampleTableValues(ctx context.Context, schema, table string, columns []string, limit int) (map[string][]string, error)
 	Close() error
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,22 @@ func (h *ClassificationHandler) CreatePattern(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"id": id.String()})
 }
 
+func (h *ClassificationHandler) TestPatterns(c *gin.Context) {
+	var req domain.TestPatternsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	columns, err := h.service.TestPatterns(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"columns": columns, "total": len(columns)})
+}
+
 func (h *ClassificationHandler) GetPattern(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -95,3 +112,165 @@ func (h *ClassificationHandler) DeletePattern(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (h *ClassificationHandler) CreateAbbreviation(c *gin.Context) {
+	var req domain.CreateAbbreviationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	id, err := h.service.CreateAbbreviation(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id.String()})
+}
+
+func (h *ClassificationHandler) ListAbbreviations(c *gin.Context) {
+	abbreviations, err := h.service.GetAllAbbreviations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"abbreviations": abbreviations, "total": len(abbreviations)})
+}
+
+func (h *ClassificationHandler) UpdateAbbreviation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid abbreviation ID"})
+		return
+	}
+
+	var req domain.CreateAbbreviationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateAbbreviation(c.Request.Context(), id, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ClassificationHandler) DeleteAbbreviation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid abbreviation ID"})
+		return
+	}
+
+	if err := h.service.DeleteAbbreviation(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ClassificationHandler) CreateInformationType(c *gin.Context) {
+	var req domain.CreateInformationTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateInformationType(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"name": req.Name})
+}
+
+func (h *ClassificationHandler) GetInformationType(c *gin.Context) {
+	infoType, err := h.service.GetInformationType(c.Request.Context(), domain.InformationType(c.Param("name")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, infoType)
+}
+
+func (h *ClassificationHandler) ListInformationTypes(c *gin.Context) {
+	infoTypes, err := h.service.GetAllInformationTypes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"information_types": infoTypes, "total": len(infoTypes)})
+}
+
+func (h *ClassificationHandler) UpdateInformationType(c *gin.Context) {
+	var req domain.CreateInformationTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateInformationType(c.Request.Context(), domain.InformationType(c.Param("name")), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ClassificationHandler) DeleteInformationType(c *gin.Context) {
+	if err := h.service.DeleteInformationType(c.Request.Context(), domain.InformationType(c.Param("name"))); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ClassificationHandler) ListPatternPacks(c *gin.Context) {
+	packs, err := h.service.ListPatternPacks(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"packs": packs, "total": len(packs)})
+}
+
+func (h *ClassificationHandler) InstallPatternPack(c *gin.Context) {
+	name := c.Param("name")
+
+	installed, err := h.service.InstallPatternPack(c.Request.Context(), name)
+	if errors.Is(err, domain.ErrPatternPackNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pack": name, "installed": installed})
+}
+
+func (h *ClassificationHandler) UninstallPatternPack(c *gin.Context) {
+	name := c.Param("name")
+
+	removed, err := h.service.UninstallPatternPack(c.Request.Context(), name)
+	if errors.Is(err, domain.ErrPatternPackNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pack": name, "removed": removed})
+}
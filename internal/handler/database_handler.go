@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -11,11 +13,13 @@ import (
 
 type DatabaseHandler struct {
 	databaseService domain.DatabaseService
+	maxUploadBytes  int64
 }
 
-func NewDatabaseHandler(databaseService domain.DatabaseService) *DatabaseHandler {
+func NewDatabaseHandler(databaseService domain.DatabaseService, maxUploadBytes int64) *DatabaseHandler {
 	return &DatabaseHandler{
 		databaseService: databaseService,
+		maxUploadBytes:  maxUploadBytes,
 	}
 }
 
@@ -31,6 +35,13 @@ func (h *DatabaseHandler) CreateDatabase(c *gin.Context) {
 	}
 
 	id, err := h.databaseService.CreateConnection(c.Request.Context(), &req)
+	if errors.Is(err, domain.ErrInvalidScanScope) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to create database connection",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create database connection",
@@ -105,6 +116,13 @@ func (h *DatabaseHandler) UpdateDatabase(c *gin.Context) {
 	}
 
 	err = h.databaseService.UpdateConnection(c.Request.Context(), id, &req)
+	if errors.Is(err, domain.ErrInvalidScanScope) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to update database connection",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update database connection",
@@ -167,3 +185,68 @@ func (h *DatabaseHandler) TestDatabase(c *gin.Context) {
 		"message": "Connection test successful",
 	})
 }
+
+// ImportSQLiteDatabase handles POST /api/v1/database/sqlite. The SQLite file is
+// sent as the multipart field "file", with optional "description" and
+// "sample_size" fields.
+func (h *DatabaseHandler) ImportSQLiteDatabase(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "SQLite file is too large",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	sampleSize := 0
+	if value := c.PostForm("sample_size"); value != "" {
+		sampleSize, err = strconv.Atoi(value)
+		if err != nil || sampleSize < 0 || sampleSize > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "sample_size must be an integer between 0 and 1000",
+			})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	id, err := h.databaseService.ImportSQLiteFile(c.Request.Context(), file, c.PostForm("description"), sampleSize)
+	if errors.Is(err, domain.ErrInvalidSQLiteFile) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to import SQLite database",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to import SQLite database",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id": id.String(),
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"database-classifier/internal/domain"
+)
+
+type FindingHandler struct {
+	findingService domain.FindingService
+}
+
+func NewFindingHandler(findingService domain.FindingService) *FindingHandler {
+	return &FindingHandler{
+		findingService: findingService,
+	}
+}
+
+// SearchFindings handles GET /api/v1/findings
+func (h *FindingHandler) SearchFindings(c *gin.Context) {
+	filter := domain.FindingFilter{
+		Schema: c.Query("schema"),
+		Table:  c.Query("table"),
+		Column: c.Query("column"),
+	}
+
+	for _, value := range queryList(c, "information_type") {
+		filter.InformationTypes = append(filter.InformationTypes, domain.InformationType(value))
+	}
+	for _, value := range queryList(c, "risk_level") {
+		filter.RiskLevels = append(filter.RiskLevels, domain.RiskLevel(value))
+	}
+	filter.DataTypes = queryList(c, "data_type")
+
+	if value := c.Query("min_confidence"); value != "" {
+		minConfidence, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid min_confidence",
+			})
+			return
+		}
+		filter.MinConfidence = minConfidence
+	}
+
+	// Invalid paging values fall back to the defaults, as in scan history
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filter.Offset = offset
+	}
+
+	findings, total, err := h.findingService.SearchFindings(c.Request.Context(), &filter)
+	if errors.Is(err, domain.ErrInvalidFindingFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to search findings",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to search findings",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"findings": findings,
+		"total":    total,
+		"limit":    filter.Limit,
+		"offset":   filter.Offset,
+	})
+}
+
+// queryList collects a query parameter that may be repeated and/or hold a
+// comma-separated list.
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
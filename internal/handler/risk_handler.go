@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+type RiskHandler struct {
+	riskService domain.RiskService
+}
+
+func NewRiskHandler(riskService domain.RiskService) *RiskHandler {
+	return &RiskHandler{
+		riskService: riskService,
+	}
+}
+
+// CreatePolicy handles POST /api/v1/risk-policies
+func (h *RiskHandler) CreatePolicy(c *gin.Context) {
+	var req domain.CreateRiskPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	id, err := h.riskService.CreatePolicy(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id.String()})
+}
+
+// GetPolicy handles GET /api/v1/risk-policies/:id
+func (h *RiskHandler) GetPolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid risk policy ID"})
+		return
+	}
+
+	policy, err := h.riskService.GetPolicy(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// ListPolicies handles GET /api/v1/risk-policies
+func (h *RiskHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.riskService.GetAllPolicies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"risk_policies": policies, "total": len(policies)})
+}
+
+// UpdatePolicy handles PUT /api/v1/risk-policies/:id
+func (h *RiskHandler) UpdatePolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid risk policy ID"})
+		return
+	}
+
+	var req domain.CreateRiskPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.riskService.UpdatePolicy(c.Request.Context(), id, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeletePolicy handles DELETE /api/v1/risk-policies/:id
+func (h *RiskHandler) DeletePolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid risk policy ID"})
+		return
+	}
+
+	if err := h.riskService.DeletePolicy(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RecomputeRisk handles POST /api/v1/database/:id/risk/recompute
+func (h *RiskHandler) RecomputeRisk(c *gin.Context) {
+	databaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid database ID"})
+		return
+	}
+
+	// The body is optional; without one the latest scans are scored under
+	// the connection's policy and nothing is stored
+	var req domain.RecomputeRiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	scans, err := h.riskService.RecomputeRisk(c.Request.Context(), databaseID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scans": scans, "total": len(scans), "applied": req.Apply})
+}
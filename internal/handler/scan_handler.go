@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,6 +13,10 @@ import (
 	"database-classifier/internal/domain"
 )
 
+// eventKeepAlive is how often an idle event stream sends a comment so that
+// proxies do not close it.
+const eventKeepAlive = 15 * time.Second
+
 type ScanHandler struct {
 	scanService domain.ScanService
 }
@@ -31,7 +38,32 @@ func (h *ScanHandler) StartScan(c *gin.Context) {
 		return
 	}
 
-	scanID, err := h.scanService.StartScan(c.Request.Context(), databaseID)
+	// The body is optional; without one a full scan of the connection's scan
+	// scope runs
+	var req domain.StartScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scanID, err := h.scanService.StartScan(c.Request.Context(), databaseID, req)
+	if errors.Is(err, domain.ErrInvalidScanScope) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to start scan",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrScanInProgress) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Failed to start scan",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to start scan",
@@ -42,7 +74,7 @@ func (h *ScanHandler) StartScan(c *gin.Context) {
 
 	c.JSON(http.StatusAccepted, gin.H{
 		"scan_id": scanID.String(),
-		"message": "Scan started successfully",
+		"message": "Scan queued successfully",
 		"status":  "pending",
 	})
 }
@@ -115,7 +147,16 @@ func (h *ScanHandler) GetLatestClassification(c *gin.Context) {
 		return
 	}
 
-	result, err := h.scanService.GetLatestClassification(c.Request.Context(), databaseID)
+	// sort=risk lists the riskiest schemas and tables first
+	sortBy := domain.ClassificationSort(c.Query("sort"))
+	if sortBy != "" && sortBy != domain.ClassificationSortRisk {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid sort, expected risk",
+		})
+		return
+	}
+
+	result, err := h.scanService.GetLatestClassification(c.Request.Context(), databaseID, sortBy)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   "No classification found",
@@ -127,6 +168,109 @@ func (h *ScanHandler) GetLatestClassification(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// DiffScans handles GET /api/v1/database/:id/scan/diff
+func (h *ScanHandler) DiffScans(c *gin.Context) {
+	idParam := c.Param("id")
+	databaseID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid database ID",
+		})
+		return
+	}
+
+	// Both scans are optional: "to" defaults to the latest completed scan and
+	// "from" to the completed scan before it
+	var fromScanID, toScanID *uuid.UUID
+	if fromParam := c.Query("from"); fromParam != "" {
+		id, err := uuid.Parse(fromParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid from scan ID",
+			})
+			return
+		}
+		fromScanID = &id
+	}
+	if toParam := c.Query("to"); toParam != "" {
+		id, err := uuid.Parse(toParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid to scan ID",
+			})
+			return
+		}
+		toScanID = &id
+	}
+
+	diff, err := h.scanService.DiffScans(c.Request.Context(), databaseID, fromScanID, toScanID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrInvalidScanDiff):
+			status = http.StatusBadRequest
+		case errors.Is(err, domain.ErrScanNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to diff scans",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// StreamScanEvents handles GET /api/v1/scan/:scanId/events. It streams the
+// scan's progress, table and done events as Server-Sent Events until the scan
+// ends or the client disconnects.
+func (h *ScanHandler) StreamScanEvents(c *gin.Context) {
+	scanIDParam := c.Param("scanId")
+	scanID, err := uuid.Parse(scanIDParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	events, err := h.scanService.WatchScan(c.Request.Context(), scanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Scan result not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// A scan outlives the server's write timeout. Should the writer not
+	// support deadlines, the stream simply ends at that timeout.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), event)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
+
 // CancelScan handles POST /api/v1/scan/:scanId/cancel
 func (h *ScanHandler) CancelScan(c *gin.Context) {
 	scanIDParam := c.Param("scanId")
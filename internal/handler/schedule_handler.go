@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+type ScheduleHandler struct {
+	scheduleService domain.ScheduleService
+}
+
+func NewScheduleHandler(scheduleService domain.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// CreateSchedule handles POST /api/v1/database/:id/schedules
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	databaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid database ID",
+		})
+		return
+	}
+
+	var req domain.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.scheduleService.CreateSchedule(c.Request.Context(), databaseID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to create schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// GetDatabaseSchedules handles GET /api/v1/database/:id/schedules
+func (h *ScheduleHandler) GetDatabaseSchedules(c *gin.Context) {
+	databaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid database ID",
+		})
+		return
+	}
+
+	schedules, err := h.scheduleService.GetSchedules(c.Request.Context(), databaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get schedules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedules": schedules,
+		"total":     len(schedules),
+	})
+}
+
+// ListSchedules handles GET /api/v1/schedules
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.scheduleService.GetAllSchedules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get schedules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedules": schedules,
+		"total":     len(schedules),
+	})
+}
+
+// PauseSchedule handles POST /api/v1/schedules/:id/pause
+func (h *ScheduleHandler) PauseSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid schedule ID",
+		})
+		return
+	}
+
+	if err := h.scheduleService.PauseSchedule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to pause schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Schedule paused successfully",
+	})
+}
+
+// ResumeSchedule handles POST /api/v1/schedules/:id/resume
+func (h *ScheduleHandler) ResumeSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid schedule ID",
+		})
+		return
+	}
+
+	if err := h.scheduleService.ResumeSchedule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to resume schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Schedule resumed successfully",
+	})
+}
+
+// DeleteSchedule handles DELETE /api/v1/schedules/:id
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid schedule ID",
+		})
+		return
+	}
+
+	if err := h.scheduleService.DeleteSchedule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Schedule deleted successfully",
+	})
+}
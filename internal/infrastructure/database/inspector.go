@@ -0,0 +1,61 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+
+	"database-classifier/internal/domain"
+)
+
+// NewInspector returns an unconnected inspector for engine. An empty engine
+// means MySQL, the only engine supported before connections recorded one.
+func NewInspector(engine domain.Engine) (domain.Inspector, error) {
+	switch engine {
+	case domain.EngineMySQL, "":
+		return NewMySQLInspector(), nil
+	case domain.EnginePostgres:
+		return NewPostgresInspector(), nil
+	case domain.EngineSQLite:
+		return NewSQLiteInspector(), nil
+	default:
+		return nil, fmt.Errorf("unsupported database engine: %s", engine)
+	}
+}
+
+// appendColumn adds column to the last table of tables, starting a new table
+// from table, which carries no columns, when the catalog rows, ordered by
+// table, move on to another one.
+func appendColumn(tables []*domain.TableInfo, table domain.TableInfo, column domain.ColumnInfo) []*domain.TableInfo {
+	if n := len(tables); n == 0 || tables[n-1].TableName != table.TableName {
+		tables = append(tables, &table)
+	}
+	last := tables[len(tables)-1]
+	last.Columns = append(last.Columns, column)
+	return tables
+}
+
+// nullableLength converts a catalog length, NULL for columns without one, to
+// ColumnInfo.MaxLength.
+func nullableLength(length sql.NullInt64) *int64 {
+	if !length.Valid {
+		return nil
+	}
+	v := length.Int64
+	return &v
+}
+
+// IsConnectionError reports whether err means the connection to the inspected
+// server was lost, as opposed to a failure confined to one object.
+func IsConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr)
+}
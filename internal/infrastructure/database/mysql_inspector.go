@@ -1,15 +1,21 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strconv"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 
 	"database-classifier/internal/domain"
 )
 
+// sampleOversampling widens the random row filter so that LIMIT, not the
+// filter, usually bounds the sample when TABLE_ROWS underestimates the table.
+const sampleOversampling = 2.0
+
 type MySQLInspector struct {
 	db *sql.DB
 }
@@ -18,7 +24,9 @@ func NewMySQLInspector() *MySQLInspector {
 	return &MySQLInspector{}
 }
 
-func (m *MySQLInspector) Connect(host string, port int, username, password string) error {
+// Connect opens information_schema, which exposes every schema the user can
+// see; database is not used to narrow the connection.
+func (m *MySQLInspector) Connect(ctx context.Context, host string, port int, username, password, database string) error {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/information_schema?parseTime=true&charset=utf8mb4",
 		username, password, host, port)
 
@@ -28,7 +36,7 @@ func (m *MySQLInspector) Connect(host string, port int, username, password strin
 	}
 
 	// Test the connection
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		db.Close()
 		return fmt.Errorf("failed to ping MySQL database: %w", err)
 	}
@@ -42,7 +50,7 @@ func (m *MySQLInspector) Connect(host string, port int, username, password strin
 	return nil
 }
 
-func (m *MySQLInspector) GetSchemas() ([]string, error) {
+func (m *MySQLInspector) GetSchemas(ctx context.Context) ([]string, error) {
 	if m.db == nil {
 		return nil, fmt.Errorf("not connected to database")
 	}
@@ -54,7 +62,7 @@ func (m *MySQLInspector) GetSchemas() ([]string, error) {
 		ORDER BY SCHEMA_NAME
 	`
 
-	rows, err := m.db.Query(query)
+	rows, err := m.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schemas: %w", err)
 	}
@@ -76,7 +84,7 @@ func (m *MySQLInspector) GetSchemas() ([]string, error) {
 	return schemas, nil
 }
 
-func (m *MySQLInspector) GetTables(schema string) ([]string, error) {
+func (m *MySQLInspector) GetTables(ctx context.Context, schema string) ([]string, error) {
 	if m.db == nil {
 		return nil, fmt.Errorf("not connected to database")
 	}
@@ -88,7 +96,7 @@ func (m *MySQLInspector) GetTables(schema string) ([]string, error) {
 		ORDER BY TABLE_NAME
 	`
 
-	rows, err := m.db.Query(query, schema)
+	rows, err := m.db.QueryContext(ctx, query, schema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables for schema %s: %w", schema, err)
 	}
@@ -110,34 +118,40 @@ func (m *MySQLInspector) GetTables(schema string) ([]string, error) {
 	return tables, nil
 }
 
-func (m *MySQLInspector) GetTableInfo(schema, table string) (*domain.MySQLTableInfo, error) {
+func (m *MySQLInspector) GetTableInfo(ctx context.Context, schema, table string) (*domain.TableInfo, error) {
 	if m.db == nil {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
 	query := `
 		SELECT 
-			COLUMN_NAME,
-			DATA_TYPE,
-			IS_NULLABLE,
-			COLUMN_DEFAULT,
-			COLUMN_KEY
-		FROM COLUMNS 
-		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
-		ORDER BY ORDINAL_POSITION
+			c.COLUMN_NAME,
+			c.DATA_TYPE,
+			c.IS_NULLABLE,
+			c.COLUMN_DEFAULT,
+			c.COLUMN_KEY,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			c.COLUMN_COMMENT,
+			t.TABLE_COMMENT
+		FROM COLUMNS c
+		JOIN TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+		WHERE c.TABLE_SCHEMA = ? AND c.TABLE_NAME = ?
+		ORDER BY c.ORDINAL_POSITION
 	`
 
-	rows, err := m.db.Query(query, schema, table)
+	rows, err := m.db.QueryContext(ctx, query, schema, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query columns for table %s.%s: %w", schema, table, err)
 	}
 	defer rows.Close()
 
-	var columns []domain.MySQLColumnInfo
+	var columns []domain.ColumnInfo
+	var tableComment string
 	for rows.Next() {
-		var column domain.MySQLColumnInfo
+		var column domain.ColumnInfo
 		var isNullable string
 		var defaultValue sql.NullString
+		var maxLength sql.NullInt64
 
 		if err := rows.Scan(
 			&column.ColumnName,
@@ -145,6 +159,9 @@ func (m *MySQLInspector) GetTableInfo(schema, table string) (*domain.MySQLTableI
 			&isNullable,
 			&defaultValue,
 			&column.ColumnKey,
+			&maxLength,
+			&column.Comment,
+			&tableComment,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan column info: %w", err)
 		}
@@ -153,6 +170,7 @@ func (m *MySQLInspector) GetTableInfo(schema, table string) (*domain.MySQLTableI
 		if defaultValue.Valid {
 			column.DefaultValue = &defaultValue.String
 		}
+		column.MaxLength = nullableLength(maxLength)
 
 		columns = append(columns, column)
 	}
@@ -161,14 +179,164 @@ func (m *MySQLInspector) GetTableInfo(schema, table string) (*domain.MySQLTableI
 		return nil, fmt.Errorf("error iterating columns: %w", err)
 	}
 
-	return &domain.MySQLTableInfo{
+	return &domain.TableInfo{
 		SchemaName: schema,
 		TableName:  table,
+		Comment:    tableComment,
 		Columns:    columns,
 	}, nil
 }
 
-func (m *MySQLInspector) TestConnection(host string, port int, username, password, database string) error {
+// GetSchemaTables versions each table by its CREATE_TIME and UPDATE_TIME.
+// InnoDB only keeps UPDATE_TIME in memory, so tables not written to since the
+// server started have no data version.
+func (m *MySQLInspector) GetSchemaTables(ctx context.Context, schema string) ([]*domain.TableInfo, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// MySQL 8 caches table statistics, UPDATE_TIME included, for a day by
+	// default; older servers do not know the variable and never cache them
+	_, _ = conn.ExecContext(ctx, "SET SESSION information_schema_stats_expiry = 0")
+
+	query := `
+		SELECT
+			c.TABLE_NAME,
+			c.COLUMN_NAME,
+			c.DATA_TYPE,
+			c.IS_NULLABLE,
+			c.COLUMN_DEFAULT,
+			c.COLUMN_KEY,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			c.COLUMN_COMMENT,
+			t.TABLE_COMMENT,
+			CONCAT(t.CREATE_TIME, '/', t.UPDATE_TIME)
+		FROM COLUMNS c
+		JOIN TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+		WHERE c.TABLE_SCHEMA = ? AND t.TABLE_TYPE = 'BASE TABLE'
+		ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION
+	`
+
+	rows, err := conn.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns for schema %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var tables []*domain.TableInfo
+	for rows.Next() {
+		var tableName string
+		var column domain.ColumnInfo
+		var isNullable string
+		var defaultValue sql.NullString
+		var maxLength sql.NullInt64
+		var tableComment string
+		var dataVersion sql.NullString
+
+		if err := rows.Scan(
+			&tableName,
+			&column.ColumnName,
+			&column.DataType,
+			&isNullable,
+			&defaultValue,
+			&column.ColumnKey,
+			&maxLength,
+			&column.Comment,
+			&tableComment,
+			&dataVersion,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+
+		column.IsNullable = isNullable == "YES"
+		if defaultValue.Valid {
+			column.DefaultValue = &defaultValue.String
+		}
+		column.MaxLength = nullableLength(maxLength)
+
+		tables = appendColumn(tables, domain.TableInfo{
+			SchemaName:  schema,
+			TableName:   tableName,
+			Comment:     tableComment,
+			DataVersion: dataVersion.String,
+		}, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return tables, nil
+}
+
+// SampleTableValues returns up to limit non-empty values per column drawn from
+// a random subset of rows of schema.table.
+func (m *MySQLInspector) SampleTableValues(ctx context.Context, schema, table string, columns []string, limit int) (map[string][]string, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	samples := make(map[string][]string, len(columns))
+	if len(columns) == 0 || limit <= 0 {
+		return samples, nil
+	}
+
+	rowCount, err := m.GetTableRowCount(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier(column)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(quoted, ", "), quoteIdentifier(schema), quoteIdentifier(table))
+	var args []any
+	if rowCount > int64(limit) {
+		query += " WHERE RAND() < ?"
+		args = append(args, sampleOversampling*float64(limit)/float64(rowCount))
+	}
+	query += " LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample table %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan sampled row: %w", err)
+		}
+		for i, value := range values {
+			if value.Valid && strings.TrimSpace(value.String) != "" {
+				samples[columns[i]] = append(samples[columns[i]], value.String)
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sampled rows: %w", err)
+	}
+
+	return samples, nil
+}
+
+func (m *MySQLInspector) TestConnection(ctx context.Context, host string, port int, username, password, database string) error {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&charset=utf8mb4",
 		username, password, host, port, database)
 
@@ -178,7 +346,7 @@ func (m *MySQLInspector) TestConnection(host string, port int, username, passwor
 	}
 	defer db.Close()
 
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		return fmt.Errorf("failed to ping MySQL database: %w", err)
 	}
 
@@ -191,7 +359,7 @@ func (m *MySQLInspector) Close() error {
 	}
 	return nil
 }
-func (m *MySQLInspector) GetDatabaseSize() (int64, error) {
+func (m *MySQLInspector) GetDatabaseSize(ctx context.Context) (int64, error) {
 	if m.db == nil {
 		return 0, fmt.Errorf("not connected to database")
 	}
@@ -203,7 +371,7 @@ func (m *MySQLInspector) GetDatabaseSize() (int64, error) {
 	`
 
 	var sizeStr string
-	err := m.db.QueryRow(query).Scan(&sizeStr)
+	err := m.db.QueryRowContext(ctx, query).Scan(&sizeStr)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query database size: %w", err)
 	}
@@ -215,7 +383,7 @@ func (m *MySQLInspector) GetDatabaseSize() (int64, error) {
 
 	return size, nil
 }
-func (m *MySQLInspector) GetTableRowCount(schema, table string) (int64, error) {
+func (m *MySQLInspector) GetTableRowCount(ctx context.Context, schema, table string) (int64, error) {
 	if m.db == nil {
 		return 0, fmt.Errorf("not connected to database")
 	}
@@ -227,7 +395,7 @@ func (m *MySQLInspector) GetTableRowCount(schema, table string) (int64, error) {
 	`
 
 	var count sql.NullInt64
-	err := m.db.QueryRow(query, schema, table).Scan(&count)
+	err := m.db.QueryRowContext(ctx, query, schema, table).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query table row count: %w", err)
 	}
@@ -238,3 +406,7 @@ func (m *MySQLInspector) GetTableRowCount(schema, table string) (int64, error) {
 
 	return 0, nil
 }
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
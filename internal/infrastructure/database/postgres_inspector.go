@@ -0,0 +1,449 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	"database-classifier/internal/domain"
+)
+
+// postgresDefaultDatabase is used when a connection does not name a database;
+// every PostgreSQL server has it.
+const postgresDefaultDatabase = "postgres"
+
+// pgCharMaxLength selects the declared length of char and varchar columns the
+// way information_schema reports CHARACTER_MAXIMUM_LENGTH; atttypmod carries
+// a 4-byte header and is -1 when no length was declared.
+const pgCharMaxLength = `CASE
+				WHEN a.atttypid IN ('pg_catalog.bpchar'::regtype, 'pg_catalog.varchar'::regtype) AND a.atttypmod > 0
+				THEN a.atttypmod - 4
+			END`
+
+// PostgresInspector inspects a single PostgreSQL database. Unlike MySQL, a
+// PostgreSQL connection is bound to one database, so the schemas it reports
+// are the schemas of that database.
+type PostgresInspector struct {
+	db *sql.DB
+}
+
+func NewPostgresInspector() *PostgresInspector {
+	return &PostgresInspector{}
+}
+
+func (p *PostgresInspector) Connect(ctx context.Context, host string, port int, username, password, database string) error {
+	db, err := sql.Open("postgres", postgresDSN(host, port, username, password, database))
+	if err != nil {
+		return fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+	}
+
+	// Test the connection
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to ping PostgreSQL database: %w", err)
+	}
+
+	// Set connection pool settings
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(0)
+
+	p.db = db
+	return nil
+}
+
+func (p *PostgresInspector) GetSchemas(ctx context.Context) ([]string, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT nspname
+		FROM pg_catalog.pg_namespace
+		WHERE nspname <> 'information_schema' AND nspname NOT LIKE 'pg\_%'
+		ORDER BY nspname
+	`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schemaName string
+		if err := rows.Scan(&schemaName); err != nil {
+			return nil, fmt.Errorf("failed to scan schema name: %w", err)
+		}
+		schemas = append(schemas, schemaName)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// GetTables returns the ordinary and partitioned tables of schema. Partitions
+// share the columns of their parent, so they are left out and their rows are
+// reached through the parent.
+func (p *PostgresInspector) GetTables(ctx context.Context, schema string) ([]string, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT c.relname
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		ORDER BY c.relname
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables for schema %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetTableInfo reads columns from pg_catalog so that domains, enums and arrays
+// are reported by their type name. ColumnKey follows MySQL's COLUMN_KEY values:
+// PRI for primary key columns and UNI for columns with a unique index.
+func (p *PostgresInspector) GetTableInfo(ctx context.Context, schema, table string) (*domain.TableInfo, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			a.attname,
+			pg_catalog.format_type(a.atttypid, NULL),
+			NOT a.attnotnull,
+			pg_catalog.pg_get_expr(d.adbin, d.adrelid),
+			CASE
+				WHEN EXISTS (
+					SELECT 1 FROM pg_catalog.pg_index i
+					WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+				) THEN 'PRI'
+				WHEN EXISTS (
+					SELECT 1 FROM pg_catalog.pg_index i
+					WHERE i.indrelid = c.oid AND i.indisunique AND i.indnatts = 1 AND i.indkey[0] = a.attnum
+				) THEN 'UNI'
+				ELSE ''
+			END,
+			` + pgCharMaxLength + `,
+			COALESCE(pg_catalog.col_description(c.oid, a.attnum), ''),
+			COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '')
+		FROM pg_catalog.pg_attribute a
+		JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns for table %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	var columns []domain.ColumnInfo
+	var tableComment string
+	for rows.Next() {
+		var column domain.ColumnInfo
+		var defaultValue sql.NullString
+		var maxLength sql.NullInt64
+
+		if err := rows.Scan(
+			&column.ColumnName,
+			&column.DataType,
+			&column.IsNullable,
+			&defaultValue,
+			&column.ColumnKey,
+			&maxLength,
+			&column.Comment,
+			&tableComment,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+
+		if defaultValue.Valid {
+			column.DefaultValue = &defaultValue.String
+		}
+		column.MaxLength = nullableLength(maxLength)
+
+		columns = append(columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return &domain.TableInfo{
+		SchemaName: schema,
+		TableName:  table,
+		Comment:    tableComment,
+		Columns:    columns,
+	}, nil
+}
+
+// GetSchemaTables reads the columns of every table of schema as GetTableInfo
+// does. Tables without columns, which PostgreSQL allows, are kept. A table is
+// versioned by its storage file, which TRUNCATE and rewrites replace, and its
+// cumulative row change counters; partitioned tables have no storage or
+// counters of their own and no data version, whether or not the server lists
+// them in pg_stat_all_tables.
+func (p *PostgresInspector) GetSchemaTables(ctx context.Context, schema string) ([]*domain.TableInfo, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			c.relname,
+			a.attname,
+			pg_catalog.format_type(a.atttypid, NULL),
+			NOT a.attnotnull,
+			pg_catalog.pg_get_expr(d.adbin, d.adrelid),
+			CASE
+				WHEN EXISTS (
+					SELECT 1 FROM pg_catalog.pg_index i
+					WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+				) THEN 'PRI'
+				WHEN EXISTS (
+					SELECT 1 FROM pg_catalog.pg_index i
+					WHERE i.indrelid = c.oid AND i.indisunique AND i.indnatts = 1 AND i.indkey[0] = a.attnum
+				) THEN 'UNI'
+				ELSE ''
+			END,
+			` + pgCharMaxLength + `,
+			COALESCE(pg_catalog.col_description(c.oid, a.attnum), ''),
+			COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), ''),
+			CASE WHEN c.relkind = 'r' THEN c.relfilenode || '/' || (st.n_tup_ins + st.n_tup_upd + st.n_tup_del) END
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_stat_all_tables st ON st.relid = c.oid
+		LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+		LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		ORDER BY c.relname, a.attnum
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns for schema %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var tables []*domain.TableInfo
+	for rows.Next() {
+		var (
+			tableName    string
+			columnName   sql.NullString
+			dataType     sql.NullString
+			isNullable   sql.NullBool
+			defaultValue sql.NullString
+			columnKey    string
+			maxLength    sql.NullInt64
+			comment      string
+			tableComment string
+			dataVersion  sql.NullString
+		)
+
+		if err := rows.Scan(&tableName, &columnName, &dataType, &isNullable, &defaultValue, &columnKey, &maxLength, &comment, &tableComment, &dataVersion); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+
+		table := domain.TableInfo{SchemaName: schema, TableName: tableName, Comment: tableComment, DataVersion: dataVersion.String}
+		if !columnName.Valid {
+			tables = append(tables, &table)
+			continue
+		}
+
+		column := domain.ColumnInfo{
+			ColumnName: columnName.String,
+			DataType:   dataType.String,
+			IsNullable: isNullable.Bool,
+			ColumnKey:  columnKey,
+			MaxLength:  nullableLength(maxLength),
+			Comment:    comment,
+		}
+		if defaultValue.Valid {
+			column.DefaultValue = &defaultValue.String
+		}
+
+		tables = appendColumn(tables, table, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return tables, nil
+}
+
+// SampleTableValues returns up to limit non-empty values per column drawn from
+// a random subset of rows of schema.table, including the rows of its
+// partitions.
+func (p *PostgresInspector) SampleTableValues(ctx context.Context, schema, table string, columns []string, limit int) (map[string][]string, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	samples := make(map[string][]string, len(columns))
+	if len(columns) == 0 || limit <= 0 {
+		return samples, nil
+	}
+
+	rowCount, err := p.GetTableRowCount(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quotePostgresIdentifier(column) + "::text"
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(quoted, ", "), quotePostgresIdentifier(schema), quotePostgresIdentifier(table))
+	var args []any
+	if rowCount > int64(limit) {
+		query += " WHERE random() < $1 LIMIT $2"
+		args = append(args, sampleOversampling*float64(limit)/float64(rowCount), limit)
+	} else {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample table %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan sampled row: %w", err)
+		}
+		for i, value := range values {
+			if value.Valid && strings.TrimSpace(value.String) != "" {
+				samples[columns[i]] = append(samples[columns[i]], value.String)
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sampled rows: %w", err)
+	}
+
+	return samples, nil
+}
+
+func (p *PostgresInspector) TestConnection(ctx context.Context, host string, port int, username, password, database string) error {
+	db, err := sql.Open("postgres", postgresDSN(host, port, username, password, database))
+	if err != nil {
+		return fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping PostgreSQL database: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PostgresInspector) Close() error {
+	if p.db != nil {
+		return p.db.Close()
+	}
+	return nil
+}
+
+func (p *PostgresInspector) GetDatabaseSize(ctx context.Context) (int64, error) {
+	if p.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	var size int64
+	err := p.db.QueryRowContext(ctx, "SELECT pg_catalog.pg_database_size(current_database())").Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query database size: %w", err)
+	}
+
+	return size, nil
+}
+
+// GetTableRowCount returns the planner's row estimate for schema.table summed
+// over its partitions. Tables that were never analyzed count as empty.
+func (p *PostgresInspector) GetTableRowCount(ctx context.Context, schema, table string) (int64, error) {
+	if p.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT COALESCE(SUM(GREATEST(c.reltuples, 0)), 0)::bigint
+		FROM pg_catalog.pg_class c
+		WHERE (c.oid = $1::regclass AND c.relkind = 'r')
+			OR c.oid IN (SELECT relid FROM pg_catalog.pg_partition_tree($1::regclass) WHERE isleaf)
+	`
+
+	var count int64
+	err := p.db.QueryRowContext(ctx, query, quotePostgresIdentifier(schema)+"."+quotePostgresIdentifier(table)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query table row count: %w", err)
+	}
+
+	return count, nil
+}
+
+func postgresDSN(host string, port int, username, password, database string) string {
+	if database == "" {
+		database = postgresDefaultDatabase
+	}
+
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(username, password),
+		Host:   host + ":" + strconv.Itoa(port),
+		Path:   "/" + database,
+		// Like libpq, use TLS when the server offers it instead of lib/pq's
+		// default of requiring it
+		RawQuery: "sslmode=prefer",
+	}
+	return dsn.String()
+}
+
+func quotePostgresIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,361 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"database-classifier/internal/domain"
+)
+
+// SQLiteInspector inspects a SQLite database file, opened read-only so that a
+// scan never modifies the artifact it classifies.
+type SQLiteInspector struct {
+	db   *sql.DB
+	path string
+}
+
+func NewSQLiteInspector() *SQLiteInspector {
+	return &SQLiteInspector{}
+}
+
+// Connect opens the file at path; host, port, username and password are not
+// used by SQLite.
+func (s *SQLiteInspector) Connect(ctx context.Context, host string, port int, username, password, path string) error {
+	db, err := openSQLite(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	// A single connection is enough for one file and keeps the file open once
+	db.SetMaxOpenConns(1)
+
+	s.db = db
+	s.path = path
+	return nil
+}
+
+func (s *SQLiteInspector) GetSchemas(ctx context.Context) ([]string, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT name FROM pragma_database_list WHERE name <> 'temp' ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schemaName string
+		if err := rows.Scan(&schemaName); err != nil {
+			return nil, fmt.Errorf("failed to scan schema name: %w", err)
+		}
+		schemas = append(schemas, schemaName)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+func (s *SQLiteInspector) GetTables(ctx context.Context, schema string) ([]string, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT name
+		FROM %s.sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%%' ESCAPE '\'
+		ORDER BY name
+	`, quoteSQLiteIdentifier(schema))
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables for schema %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetTableInfo reads PRAGMA table_info. SQLite columns only have a declared
+// type, which is reported lower-cased and without its length, e.g. varchar
+// for VARCHAR(50); the length of text types is reported as MaxLength.
+func (s *SQLiteInspector) GetTableInfo(ctx context.Context, schema, table string) (*domain.TableInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := fmt.Sprintf("PRAGMA %s.table_info(%s)", quoteSQLiteIdentifier(schema), quoteSQLiteIdentifier(table))
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns for table %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	var columns []domain.ColumnInfo
+	for rows.Next() {
+		var (
+			cid          int
+			column       domain.ColumnInfo
+			declaredType string
+			notNull      int
+			defaultValue sql.NullString
+			pk           int
+		)
+
+		if err := rows.Scan(&cid, &column.ColumnName, &declaredType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+
+		column.DataType = sqliteBaseType(declaredType)
+		column.MaxLength = sqliteMaxLength(declaredType)
+		column.IsNullable = notNull == 0
+		if defaultValue.Valid {
+			column.DefaultValue = &defaultValue.String
+		}
+		if pk > 0 {
+			column.ColumnKey = "PRI"
+		}
+
+		columns = append(columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return &domain.TableInfo{
+		SchemaName: schema,
+		TableName:  table,
+		Columns:    columns,
+	}, nil
+}
+
+// GetSchemaTables versions every table by the modification time and size of
+// the database file and its write-ahead log, since SQLite keeps no per-table
+// change information.
+func (s *SQLiteInspector) GetSchemaTables(ctx context.Context, schema string) ([]*domain.TableInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	dataVersion := sqliteDataVersion(s.path)
+
+	query := fmt.Sprintf(`
+		SELECT m.name, p.name, p.type, p."notnull", p.dflt_value, p.pk
+		FROM %s.sqlite_master m
+		JOIN pragma_table_info(m.name, ?) p
+		WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite\_%%' ESCAPE '\'
+		ORDER BY m.name, p.cid
+	`, quoteSQLiteIdentifier(schema))
+
+	rows, err := s.db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns for schema %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var tables []*domain.TableInfo
+	for rows.Next() {
+		var (
+			tableName    string
+			column       domain.ColumnInfo
+			declaredType string
+			notNull      int
+			defaultValue sql.NullString
+			pk           int
+		)
+
+		if err := rows.Scan(&tableName, &column.ColumnName, &declaredType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+
+		column.DataType = sqliteBaseType(declaredType)
+		column.MaxLength = sqliteMaxLength(declaredType)
+		column.IsNullable = notNull == 0
+		if defaultValue.Valid {
+			column.DefaultValue = &defaultValue.String
+		}
+		if pk > 0 {
+			column.ColumnKey = "PRI"
+		}
+
+		tables = appendColumn(tables, domain.TableInfo{SchemaName: schema, TableName: tableName, DataVersion: dataVersion}, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return tables, nil
+}
+
+// SampleTableValues returns up to limit non-empty values per column drawn from
+// random rows of schema.table.
+func (s *SQLiteInspector) SampleTableValues(ctx context.Context, schema, table string, columns []string, limit int) (map[string][]string, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	samples := make(map[string][]string, len(columns))
+	if len(columns) == 0 || limit <= 0 {
+		return samples, nil
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = "CAST(" + quoteSQLiteIdentifier(column) + " AS TEXT)"
+	}
+
+	// SQLite keeps no row estimates, so the random order is applied to the
+	// whole table; files handed to the classifier are expected to be modest.
+	query := fmt.Sprintf("SELECT %s FROM %s.%s ORDER BY random() LIMIT ?",
+		strings.Join(quoted, ", "), quoteSQLiteIdentifier(schema), quoteSQLiteIdentifier(table))
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample table %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan sampled row: %w", err)
+		}
+		for i, value := range values {
+			if value.Valid && strings.TrimSpace(value.String) != "" {
+				samples[columns[i]] = append(samples[columns[i]], value.String)
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sampled rows: %w", err)
+	}
+
+	return samples, nil
+}
+
+func (s *SQLiteInspector) TestConnection(ctx context.Context, host string, port int, username, password, path string) error {
+	db, err := openSQLite(ctx, path)
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+func (s *SQLiteInspector) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// openSQLite opens path read-only and reads its catalog, which fails for files
+// that are not SQLite databases; opening alone does not touch the file.
+func openSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("failed to open SQLite file: %s is a directory", path)
+	}
+
+	dsn := url.URL{
+		Scheme:   "file",
+		Path:     path,
+		RawQuery: "mode=ro",
+	}
+
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite file: %w", err)
+	}
+
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(1) FROM sqlite_master").Scan(&tables); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read SQLite file: %w", err)
+	}
+
+	return db, nil
+}
+
+// sqliteDataVersion describes the current state of the file at path, or
+// returns an empty version if it cannot be read.
+func sqliteDataVersion(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	version := fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+
+	if wal, err := os.Stat(path + "-wal"); err == nil {
+		version += fmt.Sprintf("/%d/%d", wal.ModTime().UnixNano(), wal.Size())
+	}
+	return version
+}
+
+func sqliteBaseType(declared string) string {
+	if i := strings.IndexByte(declared, '('); i >= 0 {
+		declared = declared[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(declared))
+}
+
+// sqliteMaxLength returns the length declared for a column of text affinity,
+// e.g. 50 for VARCHAR(50). SQLite does not enforce it, but it still tells
+// what the column was meant to hold.
+func sqliteMaxLength(declared string) *int64 {
+	upper := strings.ToUpper(declared)
+	if !strings.Contains(upper, "CHAR") && !strings.Contains(upper, "TEXT") && !strings.Contains(upper, "CLOB") {
+		return nil
+	}
+
+	open, end := strings.IndexByte(declared, '('), strings.IndexByte(declared, ')')
+	if open < 0 || end < open {
+		return nil
+	}
+	length, err := strconv.ParseInt(strings.TrimSpace(declared[open+1:end]), 10, 64)
+	if err != nil || length <= 0 {
+		return nil
+	}
+	return &length
+}
+
+func quoteSQLiteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
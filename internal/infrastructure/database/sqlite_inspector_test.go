@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createSQLiteTestFile writes a SQLite database with a customers table and
+// an AUTOINCREMENT table, which makes SQLite add its internal
+// sqlite_sequence table, and returns its path.
+func createSQLiteTestFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to create SQLite file: %v", err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE customers (
+			id INTEGER PRIMARY KEY,
+			email_address VARCHAR(255) NOT NULL,
+			full_name TEXT DEFAULT 'unknown',
+			code CHAR(8),
+			age INT
+		)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY AUTOINCREMENT, total NUMERIC(10, 2))`,
+		`INSERT INTO customers (email_address, full_name, code, age) VALUES
+			('ana@example.com', 'Ana García', 'A0000001', 36),
+			('luis@example.com', 'Luis Pérez', NULL, 45)`,
+		`INSERT INTO orders (total) VALUES (12.5)`,
+	}
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to prepare SQLite file: %v\n%s", err, stmt)
+		}
+	}
+	return path
+}
+
+func connectSQLiteTest(t *testing.T, path string) *SQLiteInspector {
+	t.Helper()
+
+	inspector := NewSQLiteInspector()
+	if err := inspector.Connect(context.Background(), "", 0, "", "", path); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { inspector.Close() })
+	return inspector
+}
+
+func TestSQLiteInspectorSchemasAndTables(t *testing.T) {
+	inspector := connectSQLiteTest(t, createSQLiteTestFile(t))
+	ctx := context.Background()
+
+	schemas, err := inspector.GetSchemas(ctx)
+	if err != nil {
+		t.Fatalf("GetSchemas: %v", err)
+	}
+	if fmt.Sprint(schemas) != "[main]" {
+		t.Errorf("GetSchemas = %v, want [main]", schemas)
+	}
+
+	tables, err := inspector.GetTables(ctx, "main")
+	if err != nil {
+		t.Fatalf("GetTables: %v", err)
+	}
+	if fmt.Sprint(tables) != "[customers orders]" {
+		t.Errorf("GetTables = %v, want [customers orders] without sqlite_sequence", tables)
+	}
+}
+
+func TestSQLiteInspectorGetTableInfo(t *testing.T) {
+	inspector := connectSQLiteTest(t, createSQLiteTestFile(t))
+
+	info, err := inspector.GetTableInfo(context.Background(), "main", "customers")
+	if err != nil {
+		t.Fatalf("GetTableInfo: %v", err)
+	}
+
+	want := "id integer null=true default=- key=PRI length=- comment=\"\"\n" +
+		"email_address varchar null=false default=- key= length=255 comment=\"\"\n" +
+		"full_name text null=true default='unknown' key= length=- comment=\"\"\n" +
+		"code char null=true default=- key= length=8 comment=\"\"\n" +
+		"age int null=true default=- key= length=- comment=\"\"\n"
+	if got := describeColumns(info.Columns); got != want {
+		t.Errorf("GetTableInfo columns:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSQLiteInspectorGetSchemaTables(t *testing.T) {
+	path := createSQLiteTestFile(t)
+	inspector := connectSQLiteTest(t, path)
+	ctx := context.Background()
+
+	tables, err := inspector.GetSchemaTables(ctx, "main")
+	if err != nil {
+		t.Fatalf("GetSchemaTables: %v", err)
+	}
+	if len(tables) != 2 || tables[0].TableName != "customers" || tables[1].TableName != "orders" {
+		t.Fatalf("GetSchemaTables returned %d tables, want customers and orders", len(tables))
+	}
+
+	single, err := inspector.GetTableInfo(ctx, "main", "customers")
+	if err != nil {
+		t.Fatalf("GetTableInfo: %v", err)
+	}
+	if describeColumns(tables[0].Columns) != describeColumns(single.Columns) {
+		t.Errorf("GetSchemaTables and GetTableInfo disagree on customers:\n%s\n%s", describeColumns(tables[0].Columns), describeColumns(single.Columns))
+	}
+
+	version := tables[0].DataVersion
+	if version == "" || tables[1].DataVersion != version {
+		t.Fatalf("data versions = %q and %q, want the same file version", version, tables[1].DataVersion)
+	}
+
+	writer, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open SQLite file: %v", err)
+	}
+	defer writer.Close()
+	if _, err := writer.Exec("INSERT INTO orders (total) VALUES (99)"); err != nil {
+		t.Fatalf("failed to write SQLite file: %v", err)
+	}
+	if sqliteDataVersion(path) == version {
+		t.Errorf("data version %q did not change after a write", version)
+	}
+}
+
+func TestSQLiteInspectorSampleTableValues(t *testing.T) {
+	inspector := connectSQLiteTest(t, createSQLiteTestFile(t))
+
+	samples, err := inspector.SampleTableValues(context.Background(), "main", "customers", []string{"email_address", "code", "age"}, 10)
+	if err != nil {
+		t.Fatalf("SampleTableValues: %v", err)
+	}
+	if len(samples["email_address"]) != 2 {
+		t.Errorf("email_address samples = %v, want 2 values", samples["email_address"])
+	}
+	if fmt.Sprint(samples["code"]) != "[A0000001]" {
+		t.Errorf("code samples = %v, want the non-null value only", samples["code"])
+	}
+	if len(samples["age"]) != 2 {
+		t.Errorf("age samples = %v, want 2 values as text", samples["age"])
+	}
+}
+
+func TestSQLiteInspectorIsReadOnly(t *testing.T) {
+	inspector := connectSQLiteTest(t, createSQLiteTestFile(t))
+
+	if _, err := inspector.db.Exec("DELETE FROM customers"); err == nil {
+		t.Errorf("the inspector could modify the file it inspects")
+	}
+}
+
+func TestSQLiteInspectorConnectRejectsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(text, []byte("not a database, just some text that is long enough"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	for _, path := range []string{text, dir, filepath.Join(dir, "missing.db")} {
+		if err := NewSQLiteInspector().TestConnection(context.Background(), "", 0, "", "", path); err == nil {
+			t.Errorf("TestConnection(%s) succeeded, want an error", path)
+		}
+	}
+}
+
+func TestSQLiteDeclaredTypes(t *testing.T) {
+	tests := []struct {
+		declared  string
+		baseType  string
+		maxLength int64
+	}{
+		{"VARCHAR(50)", "varchar", 50},
+		{"NVARCHAR ( 20 )", "nvarchar", 20},
+		{"TEXT", "text", 0},
+		{"CLOB(1000)", "clob", 1000},
+		{"DECIMAL(10, 2)", "decimal", 0},
+		{"INTEGER", "integer", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := sqliteBaseType(tt.declared); got != tt.baseType {
+			t.Errorf("sqliteBaseType(%q) = %q, want %q", tt.declared, got, tt.baseType)
+		}
+		got := sqliteMaxLength(tt.declared)
+		switch {
+		case tt.maxLength == 0 && got != nil:
+			t.Errorf("sqliteMaxLength(%q) = %d, want none", tt.declared, *got)
+		case tt.maxLength != 0 && (got == nil || *got != tt.maxLength):
+			t.Errorf("sqliteMaxLength(%q) = %v, want %d", tt.declared, got, tt.maxLength)
+		}
+	}
+}
@@ -12,17 +12,26 @@ type Router struct {
 	databaseHandler       *handler.DatabaseHandler
 	scanHandler           *handler.ScanHandler
 	classificationHandler *handler.ClassificationHandler
+	scheduleHandler       *handler.ScheduleHandler
+	findingHandler        *handler.FindingHandler
+	riskHandler           *handler.RiskHandler
 }
 
 func NewRouter(
 	databaseHandler *handler.DatabaseHandler,
 	scanHandler *handler.ScanHandler,
 	classificationHandler *handler.ClassificationHandler,
+	scheduleHandler *handler.ScheduleHandler,
+	findingHandler *handler.FindingHandler,
+	riskHandler *handler.RiskHandler,
 ) *Router {
 	return &Router{
 		databaseHandler:       databaseHandler,
 		scanHandler:           scanHandler,
 		classificationHandler: classificationHandler,
+		scheduleHandler:       scheduleHandler,
+		findingHandler:        findingHandler,
+		riskHandler:           riskHandler,
 	}
 }
 
@@ -50,6 +59,7 @@ func (r *Router) SetupRoutes() *gin.Engine {
 		databases := v1.Group("/database")
 		{
 			databases.POST("", r.databaseHandler.CreateDatabase)
+			databases.POST("/sqlite", r.databaseHandler.ImportSQLiteDatabase)
 			databases.GET("", r.databaseHandler.GetAllDatabases)
 			databases.GET("/:id", r.databaseHandler.GetDatabase)
 			databases.PUT("/:id", r.databaseHandler.UpdateDatabase)
@@ -59,24 +69,78 @@ func (r *Router) SetupRoutes() *gin.Engine {
 			// Scanning routes for specific database
 			databases.POST("/:id/scan", r.scanHandler.StartScan)
 			databases.GET("/:id/scan/history", r.scanHandler.GetScanHistory)
+			databases.GET("/:id/scan/diff", r.scanHandler.DiffScans)
 			databases.GET("/:id/classification", r.scanHandler.GetLatestClassification)
+
+			// Recurring scan schedules for specific database
+			databases.POST("/:id/schedules", r.scheduleHandler.CreateSchedule)
+			databases.GET("/:id/schedules", r.scheduleHandler.GetDatabaseSchedules)
+
+			// Score past scans again under a risk policy
+			databases.POST("/:id/risk/recompute", r.riskHandler.RecomputeRisk)
 		}
 
 		// Scan management routes
 		scans := v1.Group("/scan")
 		{
 			scans.GET("/:scanId", r.scanHandler.GetScanResult)
+			scans.GET("/:scanId/events", r.scanHandler.StreamScanEvents)
 			scans.POST("/:scanId/cancel", r.scanHandler.CancelScan)
 		}
 
+		// Search across the latest completed scan of every database
+		v1.GET("/findings", r.findingHandler.SearchFindings)
+
+		riskPolicies := v1.Group("/risk-policies")
+		{
+			riskPolicies.POST("", r.riskHandler.CreatePolicy)
+			riskPolicies.GET("", r.riskHandler.ListPolicies)
+			riskPolicies.GET("/:id", r.riskHandler.GetPolicy)
+			riskPolicies.PUT("/:id", r.riskHandler.UpdatePolicy)
+			riskPolicies.DELETE("/:id", r.riskHandler.DeletePolicy)
+		}
+
+		schedules := v1.Group("/schedules")
+		{
+			schedules.GET("", r.scheduleHandler.ListSchedules)
+			schedules.POST("/:id/pause", r.scheduleHandler.PauseSchedule)
+			schedules.POST("/:id/resume", r.scheduleHandler.ResumeSchedule)
+			schedules.DELETE("/:id", r.scheduleHandler.DeleteSchedule)
+		}
+
 		patterns := v1.Group("/patterns")
 		{
 			patterns.POST("", r.classificationHandler.CreatePattern)
+			patterns.POST("/test", r.classificationHandler.TestPatterns)
 			patterns.GET("", r.classificationHandler.ListPatterns)
 			patterns.GET("/:id", r.classificationHandler.GetPattern)
 			patterns.PUT("/:id", r.classificationHandler.UpdatePattern)
 			patterns.DELETE("/:id", r.classificationHandler.DeletePattern)
 		}
+
+		abbreviations := v1.Group("/abbreviations")
+		{
+			abbreviations.POST("", r.classificationHandler.CreateAbbreviation)
+			abbreviations.GET("", r.classificationHandler.ListAbbreviations)
+			abbreviations.PUT("/:id", r.classificationHandler.UpdateAbbreviation)
+			abbreviations.DELETE("/:id", r.classificationHandler.DeleteAbbreviation)
+		}
+
+		informationTypes := v1.Group("/information-types")
+		{
+			informationTypes.POST("", r.classificationHandler.CreateInformationType)
+			informationTypes.GET("", r.classificationHandler.ListInformationTypes)
+			informationTypes.GET("/:name", r.classificationHandler.GetInformationType)
+			informationTypes.PUT("/:name", r.classificationHandler.UpdateInformationType)
+			informationTypes.DELETE("/:name", r.classificationHandler.DeleteInformationType)
+		}
+
+		packs := v1.Group("/packs")
+		{
+			packs.GET("", r.classificationHandler.ListPatternPacks)
+			packs.POST("/:name/install", r.classificationHandler.InstallPatternPack)
+			packs.DELETE("/:name", r.classificationHandler.UninstallPatternPack)
+		}
 	}
 
 	return router
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+type AbbreviationRepository struct {
+	db *sql.DB
+}
+
+func NewAbbreviationRepository(db *sql.DB) *AbbreviationRepository {
+	return &AbbreviationRepository{db: db}
+}
+
+func (r *AbbreviationRepository) Create(ctx context.Context, abbreviation *domain.Abbreviation) error {
+	query := `
+		INSERT INTO abbreviations (id, abbreviation, expansion, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		abbreviation.ID.String(),
+		abbreviation.Abbreviation,
+		abbreviation.Expansion,
+		abbreviation.CreatedAt.UTC(),
+		abbreviation.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create abbreviation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AbbreviationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Abbreviation, error) {
+	query := `
+		SELECT id, abbreviation, expansion, created_at, updated_at
+		FROM abbreviations
+		WHERE id = ?
+	`
+
+	row := r.db.QueryRowContext(ctx, query, id.String())
+	return scanAbbreviation(row)
+}
+
+func (r *AbbreviationRepository) GetAll(ctx context.Context) ([]*domain.Abbreviation, error) {
+	query := `
+		SELECT id, abbreviation, expansion, created_at, updated_at
+		FROM abbreviations
+		ORDER BY abbreviation
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query abbreviations: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*domain.Abbreviation
+	for rows.Next() {
+		abbreviation, err := scanAbbreviation(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, abbreviation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating abbreviations: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *AbbreviationRepository) Update(ctx context.Context, abbreviation *domain.Abbreviation) error {
+	query := `
+		UPDATE abbreviations
+		SET abbreviation = ?, expansion = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		abbreviation.Abbreviation,
+		abbreviation.Expansion,
+		abbreviation.UpdatedAt.UTC(),
+		abbreviation.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update abbreviation: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("abbreviation not found")
+	}
+
+	return nil
+}
+
+func (r *AbbreviationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM abbreviations WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete abbreviation: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("abbreviation not found")
+	}
+
+	return nil
+}
+
+func (r *AbbreviationRepository) ExistsByAbbreviation(ctx context.Context, abbreviation string) (bool, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT COUNT(1) FROM abbreviations WHERE abbreviation = ?", abbreviation)
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check abbreviation existence: %w", err)
+	}
+	return count > 0, nil
+}
+
+func scanAbbreviation(scanner interface {
+	Scan(dest ...any) error
+}) (*domain.Abbreviation, error) {
+	var (
+		idStr        string
+		abbreviation string
+		expansion    string
+		createdAt    time.Time
+		updatedAt    time.Time
+	)
+
+	if err := scanner.Scan(&idStr, &abbreviation, &expansion, &createdAt, &updatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("abbreviation not found")
+		}
+		return nil, fmt.Errorf("failed to scan abbreviation: %w", err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid abbreviation id: %w", err)
+	}
+
+	return &domain.Abbreviation{
+		ID:           id,
+		Abbreviation: abbreviation,
+		Expansion:    expansion,
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+	}, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -23,11 +24,25 @@ func NewClassificationPatternRepository(db *sql.DB) *ClassificationPatternReposi
 func (r *ClassificationPatternRepository) Create(ctx context.Context, pattern *domain.ClassificationPattern) error {
 	query := `
 		INSERT INTO classification_patterns (
-			id, information_type, pattern, description, priority, is_active, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, information_type, pattern, description, priority, validator, target, match_tokens, pack, table_pattern, co_columns, context_boost,
+			allowed_data_types, disallowed_data_types, min_length, max_length, is_active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(
+	coColumnsJSON, err := marshalStringList(pattern.CoColumns, "co-columns")
+	if err != nil {
+		return err
+	}
+	allowedJSON, err := marshalStringList(pattern.AllowedDataTypes, "allowed data types")
+	if err != nil {
+		return err
+	}
+	disallowedJSON, err := marshalStringList(pattern.DisallowedDataTypes, "disallowed data types")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(
 		ctx,
 		query,
 		pattern.ID.String(),
@@ -35,6 +50,17 @@ func (r *ClassificationPatternRepository) Create(ctx context.Context, pattern *d
 		pattern.Pattern,
 		pattern.Description,
 		pattern.Priority,
+		nullString(pattern.Validator),
+		nullString(string(pattern.Target)),
+		boolToInt(pattern.MatchTokens),
+		nullString(pattern.Pack),
+		nullString(pattern.TablePattern),
+		coColumnsJSON,
+		pattern.ContextBoost,
+		allowedJSON,
+		disallowedJSON,
+		nullIntPtr(pattern.MinLength),
+		nullIntPtr(pattern.MaxLength),
 		boolToInt(pattern.IsActive),
 		pattern.CreatedAt.UTC(),
 		pattern.UpdatedAt.UTC(),
@@ -48,7 +74,8 @@ func (r *ClassificationPatternRepository) Create(ctx context.Context, pattern *d
 
 func (r *ClassificationPatternRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ClassificationPattern, error) {
 	query := `
-		SELECT id, information_type, pattern, description, priority, is_active, created_at, updated_at
+		SELECT id, information_type, pattern, description, priority, validator, target, match_tokens, pack, table_pattern, co_columns, context_boost,
+			allowed_data_types, disallowed_data_types, min_length, max_length, is_active, created_at, updated_at
 		FROM classification_patterns
 		WHERE id = ?
 	`
@@ -59,7 +86,8 @@ func (r *ClassificationPatternRepository) GetByID(ctx context.Context, id uuid.U
 
 func (r *ClassificationPatternRepository) GetAll(ctx context.Context) ([]*domain.ClassificationPattern, error) {
 	query := `
-		SELECT id, information_type, pattern, description, priority, is_active, created_at, updated_at
+		SELECT id, information_type, pattern, description, priority, validator, target, match_tokens, pack, table_pattern, co_columns, context_boost,
+			allowed_data_types, disallowed_data_types, min_length, max_length, is_active, created_at, updated_at
 		FROM classification_patterns
 		ORDER BY priority DESC, created_at DESC
 	`
@@ -88,7 +116,8 @@ func (r *ClassificationPatternRepository) GetAll(ctx context.Context) ([]*domain
 
 func (r *ClassificationPatternRepository) GetActive(ctx context.Context) ([]*domain.ClassificationPattern, error) {
 	query := `
-		SELECT id, information_type, pattern, description, priority, is_active, created_at, updated_at
+		SELECT id, information_type, pattern, description, priority, validator, target, match_tokens, pack, table_pattern, co_columns, context_boost,
+			allowed_data_types, disallowed_data_types, min_length, max_length, is_active, created_at, updated_at
 		FROM classification_patterns
 		WHERE is_active = 1
 		ORDER BY priority DESC, created_at DESC
@@ -118,7 +147,8 @@ func (r *ClassificationPatternRepository) GetActive(ctx context.Context) ([]*dom
 
 func (r *ClassificationPatternRepository) GetByInformationType(ctx context.Context, infoType domain.InformationType) ([]*domain.ClassificationPattern, error) {
 	query := `
-		SELECT id, information_type, pattern, description, priority, is_active, created_at, updated_at
+		SELECT id, information_type, pattern, description, priority, validator, target, match_tokens, pack, table_pattern, co_columns, context_boost,
+			allowed_data_types, disallowed_data_types, min_length, max_length, is_active, created_at, updated_at
 		FROM classification_patterns
 		WHERE information_type = ? AND is_active = 1
 		ORDER BY priority DESC, created_at DESC
@@ -149,10 +179,25 @@ func (r *ClassificationPatternRepository) GetByInformationType(ctx context.Conte
 func (r *ClassificationPatternRepository) Update(ctx context.Context, pattern *domain.ClassificationPattern) error {
 	query := `
 		UPDATE classification_patterns
-		SET information_type = ?, pattern = ?, description = ?, priority = ?, is_active = ?, updated_at = ?
+		SET information_type = ?, pattern = ?, description = ?, priority = ?, validator = ?, target = ?, match_tokens = ?, pack = ?,
+			table_pattern = ?, co_columns = ?, context_boost = ?, allowed_data_types = ?, disallowed_data_types = ?,
+			min_length = ?, max_length = ?, is_active = ?, updated_at = ?
 		WHERE id = ?
 	`
 
+	coColumnsJSON, err := marshalStringList(pattern.CoColumns, "co-columns")
+	if err != nil {
+		return err
+	}
+	allowedJSON, err := marshalStringList(pattern.AllowedDataTypes, "allowed data types")
+	if err != nil {
+		return err
+	}
+	disallowedJSON, err := marshalStringList(pattern.DisallowedDataTypes, "disallowed data types")
+	if err != nil {
+		return err
+	}
+
 	res, err := r.db.ExecContext(
 		ctx,
 		query,
@@ -160,6 +205,17 @@ func (r *ClassificationPatternRepository) Update(ctx context.Context, pattern *d
 		pattern.Pattern,
 		pattern.Description,
 		pattern.Priority,
+		nullString(pattern.Validator),
+		nullString(string(pattern.Target)),
+		boolToInt(pattern.MatchTokens),
+		nullString(pattern.Pack),
+		nullString(pattern.TablePattern),
+		coColumnsJSON,
+		pattern.ContextBoost,
+		allowedJSON,
+		disallowedJSON,
+		nullIntPtr(pattern.MinLength),
+		nullIntPtr(pattern.MaxLength),
 		boolToInt(pattern.IsActive),
 		pattern.UpdatedAt.UTC(),
 		pattern.ID.String(),
@@ -205,21 +261,76 @@ func (r *ClassificationPatternRepository) ExistsByPattern(ctx context.Context, p
 	return count > 0, nil
 }
 
+// CountByPack returns the number of patterns installed by each pattern pack.
+func (r *ClassificationPatternRepository) CountByPack(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT pack, COUNT(1) FROM classification_patterns WHERE pack IS NOT NULL GROUP BY pack")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count patterns by pack: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			pack  string
+			count int
+		)
+		if err := rows.Scan(&pack, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan pack count: %w", err)
+		}
+		counts[pack] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pack counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// DeleteByPack deletes the patterns installed by pack and returns how many
+// there were.
+func (r *ClassificationPatternRepository) DeleteByPack(ctx context.Context, pack string) (int, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM classification_patterns WHERE pack = ?", pack)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete patterns of pack: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
+
 func scanClassificationPattern(scanner interface {
 	Scan(dest ...any) error
 }) (*domain.ClassificationPattern, error) {
 	var (
-		idStr      string
-		infoType   string
-		patternStr string
-		description sql.NullString
-		priority   int
-		isActive   int
-		createdAt  time.Time
-		updatedAt  time.Time
+		idStr          string
+		infoType       string
+		patternStr     string
+		description    sql.NullString
+		priority       int
+		validator      sql.NullString
+		target         sql.NullString
+		matchTokens    int
+		pack           sql.NullString
+		tablePattern   sql.NullString
+		coColumnsJSON  []byte
+		contextBoost   float64
+		allowedJSON    []byte
+		disallowedJSON []byte
+		minLength      sql.NullInt64
+		maxLength      sql.NullInt64
+		isActive       int
+		createdAt      time.Time
+		updatedAt      time.Time
 	)
 
-	if err := scanner.Scan(&idStr, &infoType, &patternStr, &description, &priority, &isActive, &createdAt, &updatedAt); err != nil {
+	if err := scanner.Scan(&idStr, &infoType, &patternStr, &description, &priority, &validator, &target, &matchTokens, &pack, &tablePattern, &coColumnsJSON, &contextBoost,
+		&allowedJSON, &disallowedJSON, &minLength, &maxLength, &isActive, &createdAt, &updatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("classification pattern not found")
 		}
@@ -231,15 +342,75 @@ func scanClassificationPattern(scanner interface {
 		return nil, fmt.Errorf("invalid pattern id: %w", err)
 	}
 
+	coColumns, err := unmarshalStringList(coColumnsJSON, "co-columns")
+	if err != nil {
+		return nil, err
+	}
+	allowedDataTypes, err := unmarshalStringList(allowedJSON, "allowed data types")
+	if err != nil {
+		return nil, err
+	}
+	disallowedDataTypes, err := unmarshalStringList(disallowedJSON, "disallowed data types")
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.ClassificationPattern{
-		ID:              id,
-		InformationType: domain.InformationType(infoType),
-		Pattern:         patternStr,
-		Description:     stringOrEmpty(description),
-		Priority:        priority,
-		IsActive:        isActive == 1,
-		CreatedAt:       createdAt,
-		UpdatedAt:       updatedAt,
+		ID:                  id,
+		InformationType:     domain.InformationType(infoType),
+		Pattern:             patternStr,
+		Description:         stringOrEmpty(description),
+		Priority:            priority,
+		Validator:           stringOrEmpty(validator),
+		Target:              domain.PatternTarget(stringOrEmpty(target)),
+		MatchTokens:         matchTokens == 1,
+		Pack:                stringOrEmpty(pack),
+		TablePattern:        stringOrEmpty(tablePattern),
+		CoColumns:           coColumns,
+		ContextBoost:        contextBoost,
+		AllowedDataTypes:    allowedDataTypes,
+		DisallowedDataTypes: disallowedDataTypes,
+		MinLength:           intPtrOrNil(minLength),
+		MaxLength:           intPtrOrNil(maxLength),
+		IsActive:            isActive == 1,
+		CreatedAt:           createdAt,
+		UpdatedAt:           updatedAt,
 	}, nil
 }
 
+func marshalStringList(values []string, name string) (any, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func unmarshalStringList(data []byte, name string) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", name, err)
+	}
+	return values, nil
+}
+
+func nullIntPtr(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func intPtrOrNil(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
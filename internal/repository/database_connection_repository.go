@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -21,14 +22,24 @@ func NewDatabaseConnectionRepository(db *sql.DB) *DatabaseConnectionRepository {
 }
 
 func (r *DatabaseConnectionRepository) Create(ctx context.Context, conn *domain.DatabaseConnection) error {
+	scopeJSON, err := marshalScope(conn.ScanScope)
+	if err != nil {
+		return err
+	}
+	packsJSON, err := marshalStringList(conn.PatternPacks, "pattern packs")
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO database_connections (
 			id, host, port, username, encrypted_password, database_name, description,
-			created_at, updated_at, last_scanned_at, is_active
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			created_at, updated_at, last_scanned_at, is_active, sample_size, engine, file_path, scan_scope,
+			scan_concurrency, pattern_packs, risk_policy_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(
+	_, err = r.db.ExecContext(
 		ctx,
 		query,
 		conn.ID.String(),
@@ -42,6 +53,13 @@ func (r *DatabaseConnectionRepository) Create(ctx context.Context, conn *domain.
 		conn.UpdatedAt.UTC(),
 		nullTime(conn.LastScannedAt),
 		boolToInt(conn.IsActive),
+		conn.SampleSize,
+		conn.Engine,
+		nullString(conn.FilePath),
+		scopeJSON,
+		conn.ScanConcurrency,
+		packsJSON,
+		nullUUID(conn.RiskPolicyID),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert database connection: %w", err)
@@ -53,7 +71,7 @@ func (r *DatabaseConnectionRepository) Create(ctx context.Context, conn *domain.
 func (r *DatabaseConnectionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DatabaseConnection, error) {
 	query := `
 		SELECT id, host, port, username, encrypted_password, database_name, description,
-			created_at, updated_at, last_scanned_at, is_active
+			created_at, updated_at, last_scanned_at, is_active, sample_size, engine, file_path, scan_scope, scan_concurrency, pattern_packs, risk_policy_id
 		FROM database_connections
 		WHERE id = ?
 	`
@@ -65,7 +83,7 @@ func (r *DatabaseConnectionRepository) GetByID(ctx context.Context, id uuid.UUID
 func (r *DatabaseConnectionRepository) GetAll(ctx context.Context) ([]*domain.DatabaseConnection, error) {
 	query := `
 		SELECT id, host, port, username, encrypted_password, database_name, description,
-			created_at, updated_at, last_scanned_at, is_active
+			created_at, updated_at, last_scanned_at, is_active, sample_size, engine, file_path, scan_scope, scan_concurrency, pattern_packs, risk_policy_id
 		FROM database_connections
 		ORDER BY created_at DESC
 	`
@@ -95,7 +113,7 @@ func (r *DatabaseConnectionRepository) GetAll(ctx context.Context) ([]*domain.Da
 func (r *DatabaseConnectionRepository) GetActive(ctx context.Context) ([]*domain.DatabaseConnection, error) {
 	query := `
 		SELECT id, host, port, username, encrypted_password, database_name, description,
-			created_at, updated_at, last_scanned_at, is_active
+			created_at, updated_at, last_scanned_at, is_active, sample_size, engine, file_path, scan_scope, scan_concurrency, pattern_packs, risk_policy_id
 		FROM database_connections
 		WHERE is_active = 1
 		ORDER BY created_at DESC
@@ -124,10 +142,20 @@ func (r *DatabaseConnectionRepository) GetActive(ctx context.Context) ([]*domain
 }
 
 func (r *DatabaseConnectionRepository) Update(ctx context.Context, conn *domain.DatabaseConnection) error {
+	scopeJSON, err := marshalScope(conn.ScanScope)
+	if err != nil {
+		return err
+	}
+	packsJSON, err := marshalStringList(conn.PatternPacks, "pattern packs")
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE database_connections
 		SET host = ?, port = ?, username = ?, encrypted_password = ?, database_name = ?,
-			description = ?, updated_at = ?, last_scanned_at = ?, is_active = ?
+			description = ?, updated_at = ?, last_scanned_at = ?, is_active = ?, sample_size = ?, engine = ?,
+			file_path = ?, scan_scope = ?, scan_concurrency = ?, pattern_packs = ?, risk_policy_id = ?
 		WHERE id = ?
 	`
 
@@ -143,6 +171,13 @@ func (r *DatabaseConnectionRepository) Update(ctx context.Context, conn *domain.
 		conn.UpdatedAt.UTC(),
 		nullTime(conn.LastScannedAt),
 		boolToInt(conn.IsActive),
+		conn.SampleSize,
+		conn.Engine,
+		nullString(conn.FilePath),
+		scopeJSON,
+		conn.ScanConcurrency,
+		packsJSON,
+		nullUUID(conn.RiskPolicyID),
 		conn.ID.String(),
 	)
 	if err != nil {
@@ -215,6 +250,13 @@ func scanDatabaseConnection(scanner interface {
 		updatedAt      time.Time
 		lastScannedRaw sql.NullTime
 		isActive       int
+		sampleSize     int
+		engine         string
+		filePath       sql.NullString
+		scopeJSON      []byte
+		concurrency    int
+		packsJSON      []byte
+		riskPolicyID   sql.NullString
 	)
 
 	if err := scanner.Scan(
@@ -229,6 +271,13 @@ func scanDatabaseConnection(scanner interface {
 		&updatedAt,
 		&lastScannedRaw,
 		&isActive,
+		&sampleSize,
+		&engine,
+		&filePath,
+		&scopeJSON,
+		&concurrency,
+		&packsJSON,
+		&riskPolicyID,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("database connection not found")
@@ -247,8 +296,28 @@ func scanDatabaseConnection(scanner interface {
 		lastScanned = &v
 	}
 
+	scanScope, err := unmarshalScope(scopeJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	patternPacks, err := unmarshalStringList(packsJSON, "pattern packs")
+	if err != nil {
+		return nil, err
+	}
+
+	var policyID *uuid.UUID
+	if riskPolicyID.Valid {
+		parsed, err := uuid.Parse(riskPolicyID.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid risk policy id: %w", err)
+		}
+		policyID = &parsed
+	}
+
 	return &domain.DatabaseConnection{
 		ID:                connectionID,
+		Engine:            domain.Engine(engine),
 		Host:              host,
 		Port:              port,
 		Username:          username,
@@ -259,6 +328,12 @@ func scanDatabaseConnection(scanner interface {
 		UpdatedAt:         updatedAt,
 		LastScannedAt:     lastScanned,
 		IsActive:          isActive == 1,
+		SampleSize:        sampleSize,
+		FilePath:          stringOrEmpty(filePath),
+		ScanScope:         scanScope,
+		ScanConcurrency:   concurrency,
+		PatternPacks:      patternPacks,
+		RiskPolicyID:      policyID,
 	}, nil
 }
 
@@ -276,6 +351,50 @@ func stringOrEmpty(value sql.NullString) string {
 	return ""
 }
 
+func nullString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// marshalScope stores a scan scope as JSON, or NULL when there is none.
+func marshalScope(scope *domain.ScanScope) (any, error) {
+	if scope == nil {
+		return nil, nil
+	}
+	scopeJSON, err := json.Marshal(scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scan scope: %w", err)
+	}
+	return scopeJSON, nil
+}
+
+func unmarshalScope(scopeJSON []byte) (*domain.ScanScope, error) {
+	if len(scopeJSON) == 0 {
+		return nil, nil
+	}
+	var scope domain.ScanScope
+	if err := json.Unmarshal(scopeJSON, &scope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scan scope: %w", err)
+	}
+	return &scope, nil
+}
+
+func nullInt(value int) any {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+func nullUUID(value *uuid.UUID) any {
+	if value == nil {
+		return nil
+	}
+	return value.String()
+}
+
 func nullTime(value *time.Time) any {
 	if value == nil {
 		return nil
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+type FindingRepository struct {
+	db *sql.DB
+}
+
+func NewFindingRepository(db *sql.DB) *FindingRepository {
+	return &FindingRepository{db: db}
+}
+
+// latestScansQuery selects the most recent completed scan of every database,
+// including scans that completed with errors, leaving out scans whose scope
+// was overridden by the scan request. Scans started at the same instant are
+// told apart by id so that only one of them is selected.
+const latestScansQuery = `
+	SELECT s.id, s.database_id, s.completed_at,
+		JSON_UNQUOTE(JSON_EXTRACT(s.summary_json, '$.risk_level')) AS risk_level
+	FROM scan_results s
+	WHERE s.status IN (?, ?) AND s.scope_overridden = 0 AND NOT EXISTS (
+		SELECT 1 FROM scan_results newer
+		WHERE newer.database_id = s.database_id AND newer.status IN (?, ?) AND newer.scope_overridden = 0
+			AND (newer.started_at, newer.id) > (s.started_at, s.id)
+	)
+`
+
+// Search returns the findings of the latest completed scan of each database
+// that match filter, along with the total number of matches ignoring the limit
+// and offset. Columns classified as N/A are left out unless filter names N/A
+// among its information types.
+func (r *FindingRepository) Search(ctx context.Context, filter domain.FindingFilter) ([]*domain.Finding, int, error) {
+	where := []string{"1 = 1"}
+	args := []any{
+		domain.ScanStatusCompleted, domain.ScanStatusCompletedWithErrors,
+		domain.ScanStatusCompleted, domain.ScanStatusCompletedWithErrors,
+	}
+
+	if len(filter.InformationTypes) > 0 {
+		where = append(where, "c.information_type IN ("+placeholders(len(filter.InformationTypes))+")")
+		for _, infoType := range filter.InformationTypes {
+			args = append(args, infoType)
+		}
+	} else {
+		// Unclassified columns are only listed when asked for by type
+		where = append(where, "c.information_type <> ?")
+		args = append(args, domain.InfoTypeNA)
+	}
+	if filter.MinConfidence > 0 {
+		where = append(where, "c.confidence_score >= ?")
+		args = append(args, filter.MinConfidence)
+	}
+	// Names are stored with a binary collation; the globs still match them
+	// ignoring case and accents
+	if filter.Schema != "" {
+		where = append(where, `c.schema_name COLLATE utf8mb4_0900_ai_ci LIKE ? ESCAPE '\\'`)
+		args = append(args, globToLike(filter.Schema))
+	}
+	if filter.Table != "" {
+		where = append(where, `c.table_name COLLATE utf8mb4_0900_ai_ci LIKE ? ESCAPE '\\'`)
+		args = append(args, globToLike(filter.Table))
+	}
+	if filter.Column != "" {
+		where = append(where, `c.column_name COLLATE utf8mb4_0900_ai_ci LIKE ? ESCAPE '\\'`)
+		args = append(args, globToLike(filter.Column))
+	}
+	if len(filter.RiskLevels) > 0 {
+		where = append(where, "latest.risk_level IN ("+placeholders(len(filter.RiskLevels))+")")
+		for _, level := range filter.RiskLevels {
+			args = append(args, level)
+		}
+	}
+	if len(filter.DataTypes) > 0 {
+		where = append(where, "c.data_type IN ("+placeholders(len(filter.DataTypes))+")")
+		for _, dataType := range filter.DataTypes {
+			args = append(args, dataType)
+		}
+	}
+
+	from := `
+		FROM scan_columns c
+		JOIN (` + latestScansQuery + `) latest ON latest.id = c.scan_id
+		JOIN database_connections d ON d.id = c.database_id
+		WHERE ` + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(1) "+from, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count findings: %w", err)
+	}
+
+	query := `
+		SELECT c.database_id, d.host, d.port, d.database_name, c.scan_id, latest.completed_at, latest.risk_level,
+			c.schema_name, c.table_name, c.column_name, c.data_type, c.information_type, c.confidence_score, c.matched_patterns
+	` + from + `
+		ORDER BY c.confidence_score DESC, d.host, c.schema_name, c.table_name, c.column_name
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query findings: %w", err)
+	}
+	defer rows.Close()
+
+	var findings []*domain.Finding
+	for rows.Next() {
+		finding, err := scanFinding(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		findings = append(findings, finding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating findings: %w", err)
+	}
+
+	return findings, total, nil
+}
+
+func scanFinding(scanner interface {
+	Scan(dest ...any) error
+}) (*domain.Finding, error) {
+	var (
+		dbIDStr         string
+		host            string
+		port            int
+		databaseName    sql.NullString
+		scanIDStr       string
+		completedRaw    sql.NullTime
+		riskLevel       sql.NullString
+		schemaName      string
+		tableName       string
+		columnName      string
+		dataType        string
+		infoType        string
+		confidence      float64
+		matchedPatterns []byte
+	)
+
+	if err := scanner.Scan(
+		&dbIDStr,
+		&host,
+		&port,
+		&databaseName,
+		&scanIDStr,
+		&completedRaw,
+		&riskLevel,
+		&schemaName,
+		&tableName,
+		&columnName,
+		&dataType,
+		&infoType,
+		&confidence,
+		&matchedPatterns,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan finding: %w", err)
+	}
+
+	dbID, err := uuid.Parse(dbIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database id: %w", err)
+	}
+
+	scanID, err := uuid.Parse(scanIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan id: %w", err)
+	}
+
+	var scannedAt *time.Time
+	if completedRaw.Valid {
+		v := completedRaw.Time
+		scannedAt = &v
+	}
+
+	patterns := []string{}
+	if len(matchedPatterns) > 0 {
+		if err := json.Unmarshal(matchedPatterns, &patterns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal matched patterns: %w", err)
+		}
+	}
+
+	return &domain.Finding{
+		DatabaseID:      dbID,
+		Host:            host,
+		Port:            port,
+		DatabaseName:    stringOrEmpty(databaseName),
+		ScanID:          scanID,
+		ScannedAt:       scannedAt,
+		RiskLevel:       domain.RiskLevel(stringOrEmpty(riskLevel)),
+		SchemaName:      schemaName,
+		TableName:       tableName,
+		ColumnName:      columnName,
+		DataType:        dataType,
+		InformationType: domain.InformationType(infoType),
+		ConfidenceScore: confidence,
+		MatchedPatterns: patterns,
+	}, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// globToLike translates a * and ? wildcard pattern into a LIKE pattern that
+// escapes LIKE's own wildcards with a backslash.
+func globToLike(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteRune('%')
+		case '?':
+			b.WriteRune('_')
+		case '%', '_', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"database-classifier/internal/domain"
+)
+
+type InformationTypeRepository struct {
+	db *sql.DB
+}
+
+func NewInformationTypeRepository(db *sql.DB) *InformationTypeRepository {
+	return &InformationTypeRepository{db: db}
+}
+
+func (r *InformationTypeRepository) Create(ctx context.Context, infoType *domain.InformationTypeDefinition) error {
+	query := `
+		INSERT INTO information_types (name, description, sensitivity, parent, regulatory_tags, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	tagsJSON, err := marshalStringList(infoType.RegulatoryTags, "regulatory tags")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		infoType.Name,
+		infoType.Description,
+		infoType.Sensitivity,
+		nullString(string(infoType.Parent)),
+		tagsJSON,
+		infoType.CreatedAt.UTC(),
+		infoType.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create information type: %w", err)
+	}
+
+	return nil
+}
+
+func (r *InformationTypeRepository) GetByName(ctx context.Context, name domain.InformationType) (*domain.InformationTypeDefinition, error) {
+	query := `
+		SELECT name, description, sensitivity, parent, regulatory_tags, created_at, updated_at
+		FROM information_types
+		WHERE name = ?
+	`
+
+	row := r.db.QueryRowContext(ctx, query, name)
+	return scanInformationType(row)
+}
+
+func (r *InformationTypeRepository) GetAll(ctx context.Context) ([]*domain.InformationTypeDefinition, error) {
+	query := `
+		SELECT name, description, sensitivity, parent, regulatory_tags, created_at, updated_at
+		FROM information_types
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query information types: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*domain.InformationTypeDefinition
+	for rows.Next() {
+		infoType, err := scanInformationType(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, infoType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating information types: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *InformationTypeRepository) Update(ctx context.Context, infoType *domain.InformationTypeDefinition) error {
+	query := `
+		UPDATE information_types
+		SET description = ?, sensitivity = ?, parent = ?, regulatory_tags = ?, updated_at = ?
+		WHERE name = ?
+	`
+
+	tagsJSON, err := marshalStringList(infoType.RegulatoryTags, "regulatory tags")
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		infoType.Description,
+		infoType.Sensitivity,
+		nullString(string(infoType.Parent)),
+		tagsJSON,
+		infoType.UpdatedAt.UTC(),
+		infoType.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update information type: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("information type not found")
+	}
+
+	return nil
+}
+
+func (r *InformationTypeRepository) Delete(ctx context.Context, name domain.InformationType) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM information_types WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete information type: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("information type not found")
+	}
+
+	return nil
+}
+
+func scanInformationType(scanner interface {
+	Scan(dest ...any) error
+}) (*domain.InformationTypeDefinition, error) {
+	var (
+		name        string
+		description sql.NullString
+		sensitivity string
+		parent      sql.NullString
+		tagsJSON    []byte
+		createdAt   time.Time
+		updatedAt   time.Time
+	)
+
+	if err := scanner.Scan(&name, &description, &sensitivity, &parent, &tagsJSON, &createdAt, &updatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("information type not found")
+		}
+		return nil, fmt.Errorf("failed to scan information type: %w", err)
+	}
+
+	tags, err := unmarshalStringList(tagsJSON, "regulatory tags")
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.InformationTypeDefinition{
+		Name:           domain.InformationType(name),
+		Description:    stringOrEmpty(description),
+		Sensitivity:    domain.SensitivityTier(sensitivity),
+		Parent:         domain.InformationType(stringOrEmpty(parent)),
+		RegulatoryTags: tags,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}, nil
+}
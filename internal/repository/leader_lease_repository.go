@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type LeaderLeaseRepository struct {
+	db *sql.DB
+}
+
+func NewLeaderLeaseRepository(db *sql.DB) *LeaderLeaseRepository {
+	return &LeaderLeaseRepository{db: db}
+}
+
+// TryAcquire takes or renews the named lease for holder for ttl. A lease held
+// by someone else is only taken over once it has expired. Expiry is computed
+// with the metadata server clock so replicas with skewed clocks agree.
+func (r *LeaderLeaseRepository) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	// MySQL evaluates the assignments left to right, so expires_at is only
+	// extended when the preceding assignment left holder as ours.
+	query := `
+		INSERT INTO leader_leases (name, holder, expires_at)
+		VALUES (?, ?, DATE_ADD(UTC_TIMESTAMP(6), INTERVAL ? MICROSECOND))
+		ON DUPLICATE KEY UPDATE
+			holder = IF(holder = VALUES(holder) OR expires_at < UTC_TIMESTAMP(6), VALUES(holder), holder),
+			expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, name, holder, ttl.Microseconds()); err != nil {
+		return false, fmt.Errorf("failed to acquire leader lease: %w", err)
+	}
+
+	var current string
+	if err := r.db.QueryRowContext(ctx, "SELECT holder FROM leader_leases WHERE name = ?", name).Scan(&current); err != nil {
+		return false, fmt.Errorf("failed to read leader lease: %w", err)
+	}
+
+	return current == holder, nil
+}
+
+func (r *LeaderLeaseRepository) Release(ctx context.Context, name, holder string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM leader_leases WHERE name = ? AND holder = ?", name, holder)
+	if err != nil {
+		return fmt.Errorf("failed to release leader lease: %w", err)
+	}
+	return nil
+}
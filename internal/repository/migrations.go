@@ -0,0 +1,664 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+const (
+	migrationLockName    = "classifier_meta_migrations"
+	migrationLockTimeout = 60
+)
+
+// migration upgrades a metadata database created from an older
+// docker/mysql-init.sql. MySQL commits DDL implicitly, so every migration must
+// be safe to re-run after a partial failure.
+type migration struct {
+	version int
+	name    string
+	up      func(ctx context.Context, conn *sql.Conn) error
+}
+
+var migrations = []migration{
+	{version: 1, name: "add_connection_sample_size", up: migrateConnectionSampleSize},
+	{version: 2, name: "add_pattern_validator", up: migratePatternValidator},
+	{version: 3, name: "add_scan_heartbeat", up: migrateScanHeartbeat},
+	{version: 4, name: "add_scan_jobs", up: migrateScanJobs},
+	{version: 5, name: "add_scan_schedules", up: migrateScanSchedules},
+	{version: 6, name: "normalize_scan_findings", up: migrateScanFindings},
+	{version: 7, name: "add_connection_engine", up: migrateConnectionEngine},
+	{version: 8, name: "add_connection_file_path", up: migrateConnectionFilePath},
+	{version: 9, name: "add_scan_scope", up: migrateScanScope},
+	{version: 10, name: "add_connection_scan_concurrency", up: migrateConnectionScanConcurrency},
+	{version: 11, name: "add_scan_progress", up: migrateScanProgress},
+	{version: 12, name: "add_scan_errors", up: migrateScanErrors},
+	{version: 13, name: "add_incremental_scans", up: migrateIncrementalScans},
+	{version: 14, name: "add_pattern_context", up: migratePatternContext},
+	{version: 15, name: "add_pattern_type_constraints", up: migratePatternTypeConstraints},
+	{version: 16, name: "add_pattern_target", up: migratePatternTarget},
+	{version: 17, name: "add_abbreviations", up: migrateAbbreviations},
+	{version: 18, name: "add_pattern_packs", up: migratePatternPacks},
+	{version: 19, name: "add_information_types", up: migrateInformationTypes},
+	{version: 20, name: "add_risk_policies", up: migrateRiskPolicies},
+	{version: 21, name: "upgrade_stock_patterns", up: migrateStockPatterns},
+	{version: 22, name: "widen_scan_findings_names", up: migrateScanFindingsNames},
+	{version: 23, name: "add_scan_scope_overridden", up: migrateScanScopeOverridden},
+}
+
+// Migrate applies the metadata migrations that have not been recorded in
+// schema_migrations yet. A named lock keeps replicas that start together from
+// applying the same migration twice.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return fmt.Errorf("timed out waiting for migration lock")
+	}
+	defer func() {
+		var released sql.NullInt64
+		if err := conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", migrationLockName).Scan(&released); err != nil {
+			fmt.Printf("Warning: failed to release migration lock: %v\n", err)
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at DATETIME(6) NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		if err := m.up(ctx, conn); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+
+		_, err := conn.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.version, m.name, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+		}
+	}
+
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]bool, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		applied[version] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schema_migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// migrateConnectionSampleSize adds the per-connection value sampling size.
+func migrateConnectionSampleSize(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "database_connections", "sample_size", "INT NOT NULL DEFAULT 0")
+}
+
+// migratePatternValidator adds the value validator of a pattern.
+func migratePatternValidator(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "classification_patterns", "validator", "VARCHAR(64) NULL")
+}
+
+// migrateScanHeartbeat adds the heartbeat a running scan refreshes so
+// orphaned scans can be told apart from live ones.
+func migrateScanHeartbeat(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "scan_results", "heartbeat_at", "DATETIME(6) NULL")
+}
+
+// migrateScanJobs creates the queue of scans waiting for a worker.
+func migrateScanJobs(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS scan_jobs (
+			scan_id CHAR(36) PRIMARY KEY,
+			database_id CHAR(36) NOT NULL,
+			target VARCHAR(512) NOT NULL,
+			enqueued_at DATETIME(6) NOT NULL,
+			claimed_by VARCHAR(255) NULL,
+			claimed_at DATETIME(6) NULL,
+			UNIQUE KEY uq_scan_jobs_database (database_id),
+			INDEX idx_scan_jobs_enqueued_at (enqueued_at)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create scan_jobs table: %w", err)
+	}
+
+	return nil
+}
+
+// migrateScanSchedules creates the recurring scan schedules and the lease
+// that elects the replica firing them.
+func migrateScanSchedules(ctx context.Context, conn *sql.Conn) error {
+	statements := []string{`
+		CREATE TABLE IF NOT EXISTS scan_schedules (
+			id CHAR(36) PRIMARY KEY,
+			database_id CHAR(36) NOT NULL,
+			cron_expression VARCHAR(128) NULL,
+			interval_seconds INT NULL,
+			timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+			is_paused TINYINT(1) NOT NULL DEFAULT 0,
+			next_run_at DATETIME(6) NOT NULL,
+			last_run_at DATETIME(6) NULL,
+			last_scan_id CHAR(36) NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			INDEX idx_schedule_database (database_id),
+			INDEX idx_schedule_due (is_paused, next_run_at)
+		)
+	`, `
+		CREATE TABLE IF NOT EXISTS leader_leases (
+			name VARCHAR(64) PRIMARY KEY,
+			holder VARCHAR(255) NOT NULL,
+			expires_at DATETIME(6) NOT NULL
+		)
+	`}
+
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create scan schedule tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// migrateScanFindings creates the normalized findings tables and backfills
+// them from the schemas_json blob of every completed scan.
+func migrateScanFindings(ctx context.Context, conn *sql.Conn) error {
+	statements := []string{`
+		CREATE TABLE IF NOT EXISTS scan_tables (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			scan_id CHAR(36) NOT NULL,
+			database_id CHAR(36) NOT NULL,
+			schema_name VARCHAR(64) NOT NULL,
+			table_name VARCHAR(64) NOT NULL,
+			total_columns INT NOT NULL,
+			classified_columns INT NOT NULL,
+			UNIQUE KEY uq_scan_tables_table (scan_id, schema_name, table_name),
+			INDEX idx_scan_tables_database (database_id),
+			CONSTRAINT fk_scan_tables_scan FOREIGN KEY (scan_id) REFERENCES scan_results (id) ON DELETE CASCADE
+		)`, `
+		CREATE TABLE IF NOT EXISTS scan_columns (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			scan_id CHAR(36) NOT NULL,
+			database_id CHAR(36) NOT NULL,
+			schema_name VARCHAR(64) NOT NULL,
+			table_name VARCHAR(64) NOT NULL,
+			column_name VARCHAR(64) NOT NULL,
+			data_type VARCHAR(64) NOT NULL,
+			information_type VARCHAR(64) NOT NULL,
+			confidence_score DOUBLE NOT NULL,
+			matched_patterns TEXT NULL,
+			is_nullable TINYINT(1) NOT NULL,
+			sample_size INT NOT NULL DEFAULT 0,
+			matched_samples INT NOT NULL DEFAULT 0,
+			INDEX idx_scan_columns_scan (scan_id, schema_name, table_name),
+			INDEX idx_scan_columns_database (database_id),
+			INDEX idx_scan_columns_type (information_type, confidence_score),
+			INDEX idx_scan_columns_confidence (confidence_score),
+			CONSTRAINT fk_scan_columns_scan FOREIGN KEY (scan_id) REFERENCES scan_results (id) ON DELETE CASCADE
+		)`,
+	}
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create findings table: %w", err)
+		}
+	}
+
+	// Scans that already have rows were written by a previous, interrupted run.
+	rows, err := conn.QueryContext(ctx, `
+		SELECT id
+		FROM scan_results
+		WHERE status = ? AND NOT EXISTS (SELECT 1 FROM scan_tables t WHERE t.scan_id = scan_results.id)
+	`, domain.ScanStatusCompleted)
+	if err != nil {
+		return fmt.Errorf("failed to query scans to backfill: %w", err)
+	}
+
+	var scanIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan result id: %w", err)
+		}
+		scanIDs = append(scanIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating scans to backfill: %w", err)
+	}
+	rows.Close()
+
+	for _, id := range scanIDs {
+		if err := backfillScan(ctx, conn, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func backfillScan(ctx context.Context, conn *sql.Conn, id string) error {
+	scanID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid scan id: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin backfill transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The columns after summary_json are only added by later migrations and
+	// findings do not use them
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, database_id, started_at, completed_at, heartbeat_at, status, error_message, schemas_json, summary_json,
+			NULL AS scope_json, NULL AS progress_json, NULL AS errors_json, 0 AS incremental, NULL AS base_scan_id, 0 AS scope_overridden
+		FROM scan_results
+		WHERE id = ?
+	`, scanID.String())
+	result, err := scanScanResult(row)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFindings(ctx, tx, result); err != nil {
+		return fmt.Errorf("failed to backfill scan %s: %w", scanID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit backfill of scan %s: %w", scanID, err)
+	}
+
+	return nil
+}
+
+// migrateConnectionEngine records the engine of every connection; connections
+// created before PostgreSQL support are MySQL.
+func migrateConnectionEngine(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "database_connections", "engine", "VARCHAR(16) NOT NULL DEFAULT 'mysql'")
+}
+
+// migrateConnectionFilePath stores the database file of SQLite connections.
+func migrateConnectionFilePath(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "database_connections", "file_path", "VARCHAR(1024) NULL")
+}
+
+// migrateScanScope stores the scan scope of connections and the effective
+// scope each scan ran with.
+func migrateScanScope(ctx context.Context, conn *sql.Conn) error {
+	if err := addColumnIfMissing(ctx, conn, "database_connections", "scan_scope", "TEXT NULL"); err != nil {
+		return err
+	}
+	return addColumnIfMissing(ctx, conn, "scan_results", "scope_json", "TEXT NULL")
+}
+
+// migrateConnectionScanConcurrency adds the per-connection table concurrency;
+// 0 keeps existing connections on the server default.
+func migrateConnectionScanConcurrency(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "database_connections", "scan_concurrency", "INT NOT NULL DEFAULT 0")
+}
+
+// migrateScanProgress stores the progress a running scan reports.
+func migrateScanProgress(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "scan_results", "progress_json", "TEXT NULL")
+}
+
+// migrateScanErrors stores the schema- and table-level errors a scan
+// tolerated.
+func migrateScanErrors(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "scan_results", "errors_json", "TEXT NULL")
+}
+
+// migrateIncrementalScans records which scans and schedules are incremental
+// and the scan an incremental scan reused tables from.
+func migrateIncrementalScans(ctx context.Context, conn *sql.Conn) error {
+	columns := []struct{ table, column, definition string }{
+		{"scan_results", "incremental", "TINYINT(1) NOT NULL DEFAULT 0"},
+		{"scan_results", "base_scan_id", "CHAR(36) NULL"},
+		{"scan_schedules", "incremental", "TINYINT(1) NOT NULL DEFAULT 0"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(ctx, conn, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migratePatternContext adds the table and co-column conditions of
+// classification patterns; existing patterns have none.
+func migratePatternContext(ctx context.Context, conn *sql.Conn) error {
+	columns := []struct{ column, definition string }{
+		{"table_pattern", "VARCHAR(255) NULL"},
+		{"co_columns", "TEXT NULL"},
+		{"context_boost", "DOUBLE NOT NULL DEFAULT 0"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(ctx, conn, "classification_patterns", c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migratePatternTypeConstraints adds the data type and length constraints of
+// classification patterns; existing patterns have none.
+func migratePatternTypeConstraints(ctx context.Context, conn *sql.Conn) error {
+	columns := []struct{ column, definition string }{
+		{"allowed_data_types", "TEXT NULL"},
+		{"disallowed_data_types", "TEXT NULL"},
+		{"min_length", "INT NULL"},
+		{"max_length", "INT NULL"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(ctx, conn, "classification_patterns", c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migratePatternTarget adds the text a classification pattern is matched
+// against; existing patterns keep matching column names.
+func migratePatternTarget(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "classification_patterns", "target", "VARCHAR(16) NULL")
+}
+
+// migrateAbbreviations adds the abbreviation dictionary and lets patterns
+// match normalized column name tokens; existing patterns do not.
+func migrateAbbreviations(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS abbreviations (
+			id CHAR(36) PRIMARY KEY,
+			abbreviation VARCHAR(64) NOT NULL UNIQUE,
+			expansion VARCHAR(255) NOT NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create abbreviations table: %w", err)
+	}
+
+	return addColumnIfMissing(ctx, conn, "classification_patterns", "match_tokens", "TINYINT(1) NOT NULL DEFAULT 0")
+}
+
+// migratePatternPacks records the pack that installed a classification pattern
+// and the packs enabled per connection; existing patterns are core patterns
+// and existing connections enable no packs.
+func migratePatternPacks(ctx context.Context, conn *sql.Conn) error {
+	if err := addColumnIfMissing(ctx, conn, "classification_patterns", "pack", "VARCHAR(64) NULL"); err != nil {
+		return err
+	}
+	return addColumnIfMissing(ctx, conn, "database_connections", "pattern_packs", "TEXT NULL")
+}
+
+// migrateInformationTypes adds the information type catalog. It starts empty;
+// the API seeds it, along with the types existing patterns use, on startup.
+func migrateInformationTypes(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS information_types (
+			name VARCHAR(64) PRIMARY KEY,
+			description TEXT,
+			sensitivity VARCHAR(16) NOT NULL,
+			parent VARCHAR(64) NULL,
+			regulatory_tags TEXT NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create information_types table: %w", err)
+	}
+	return nil
+}
+
+// migrateRiskPolicies adds the risk policies and their assignment to
+// connections; existing connections keep the built-in risk calculation.
+func migrateRiskPolicies(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS risk_policies (
+			id CHAR(36) PRIMARY KEY,
+			name VARCHAR(128) NOT NULL UNIQUE,
+			description TEXT,
+			type_weights TEXT NULL,
+			tier_weights TEXT NULL,
+			min_confidence DOUBLE NOT NULL DEFAULT 0,
+			table_aggregation VARCHAR(16) NOT NULL,
+			database_aggregation VARCHAR(16) NOT NULL,
+			medium_threshold DOUBLE NOT NULL,
+			high_threshold DOUBLE NOT NULL,
+			critical_threshold DOUBLE NOT NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create risk_policies table: %w", err)
+	}
+
+	return addColumnIfMissing(ctx, conn, "database_connections", "risk_policy_id", "CHAR(36) NULL")
+}
+
+// Data types the stock patterns rule out or require, as of
+// configs/patterns.json when migrateStockPatterns was written.
+var (
+	temporalAndFloatTypes = []string{"date", "datetime", "timestamp", "timestamp without time zone", "timestamp with time zone", "time", "boolean", "float", "double", "double precision", "real"}
+	nonTextTypes          = append(append([]string{}, temporalAndFloatTypes...), "int", "integer", "bigint", "smallint", "tinyint", "decimal", "numeric")
+	dateAndTextTypes      = []string{"date", "datetime", "timestamp", "timestamp without time zone", "timestamp with time zone", "char", "character", "varchar", "character varying", "text"}
+)
+
+// stockPatternUpgrade is what a stock pattern seeded by an older
+// configs/patterns.json gained since.
+type stockPatternUpgrade struct {
+	pattern             string
+	validator           string
+	matchTokens         bool
+	allowedDataTypes    []string
+	disallowedDataTypes []string
+	minLength           int
+}
+
+var stockPatternUpgrades = []stockPatternUpgrade{
+	{pattern: "(?i)^(first_?name|fname|given_?name|forename)$", matchTokens: true},
+	{pattern: "(?i)^(last_?name|lname|surname|family_?name)$", matchTokens: true},
+	{pattern: "(?i)^(email|email_?address|e_?mail|user_?email|contact_?email)$", validator: "email", matchTokens: true, disallowedDataTypes: nonTextTypes, minLength: 6},
+	{pattern: "(?i)^(phone|phone_?number|telephone|tel|mobile|cell|contact_?number)$", validator: "phone", matchTokens: true, disallowedDataTypes: temporalAndFloatTypes, minLength: 7},
+	{pattern: "(?i)^(credit_?card|card_?number|cc_?number|payment_?card|card_?num)$", validator: "luhn", matchTokens: true, disallowedDataTypes: temporalAndFloatTypes, minLength: 12},
+	{pattern: "(?i)^(account_?number|account_?num|acc_?number|bank_?account)$", matchTokens: true},
+	{pattern: "(?i)^(ssn|social_?security|social_?security_?number|sin)$", validator: "us_ssn", matchTokens: true, disallowedDataTypes: temporalAndFloatTypes, minLength: 9},
+	{pattern: "(?i)^(passport|passport_?number|passport_?num|travel_?document)$", matchTokens: true},
+	{pattern: "(?i)^(ip|ip_?address|inet_?addr|network_?address)$", validator: "ip", allowedDataTypes: []string{"char", "character", "varchar", "character varying", "text", "inet", "binary", "varbinary", "int", "integer", "bigint"}},
+	{pattern: "(?i)^(mac|mac_?address|hardware_?address|ether_?addr)$", validator: "mac", allowedDataTypes: []string{"char", "character", "varchar", "character varying", "text", "macaddr", "macaddr8", "binary", "varbinary", "bigint"}},
+	{pattern: "(?i)^(postal_?code|zip_?code|zip|postcode|post_?code)$", matchTokens: true},
+	{pattern: "(?i)^(date_?of_?birth|dob|birth_?date|birthdate)$", matchTokens: true, allowedDataTypes: dateAndTextTypes},
+	{pattern: "(?i)^(national_?id|citizen_?id|id_?number|dni|cedula)$", matchTokens: true},
+	{pattern: "(?i)^(bank_?account|routing_?number|iban|swift|sort_?code)$", matchTokens: true},
+	{pattern: "(?i)^(driver_?license|driving_?license|dl_?number|license_?num)$", matchTokens: true},
+}
+
+// stockPatternAdditions are the stock patterns added since the first
+// configs/patterns.json.
+var stockPatternAdditions = []domain.ClassificationPattern{
+	{InformationType: domain.InfoTypeFullName, Pattern: "(?i)^name$", Description: "Matches a bare name column in tables that hold people", Priority: 85,
+		TablePattern: "(?i)(user|customer|client|person|people|employee|staff|member|contact|patient|student|author|owner)"},
+	{InformationType: domain.InfoTypeAddress, Pattern: "(?i)^(line_?[12]|address_?line_?[12]|street_?line_?[12]|location)$", Description: "Matches address line columns, boosted next to city and postal code columns", Priority: 40,
+		CoColumns: []string{"(?i)^(city|town)$", "(?i)^(postal_?code|zip_?code|zip|postcode|post_?code)$"}, ContextBoost: 0.3},
+	{InformationType: domain.InfoTypeNationalID, Pattern: `(?i)\b(tax ?(payer )?id|taxpayer identification|national id|citizen id|nif|rfc|cuit|cuil|dni)\b`, Description: "Matches column comments describing tax or national ID numbers", Priority: 80,
+		Target: domain.PatternTargetComment},
+	{InformationType: domain.InfoTypeEmailAddress, Pattern: `(?i)\be-?mail( address)?\b`, Description: "Matches column comments describing email addresses", Priority: 80,
+		Validator: "email", Target: domain.PatternTargetComment},
+	{InformationType: domain.InfoTypePhoneNumber, Pattern: `(?i)\b(phone|telephone|mobile)( number)?\b`, Description: "Matches column comments describing phone numbers", Priority: 75,
+		Validator: "phone", Target: domain.PatternTargetComment},
+	{InformationType: domain.InfoTypeDateOfBirth, Pattern: `(?i)\b(date of birth|birth ?date|birthday)\b`, Description: "Matches column comments describing dates of birth", Priority: 80,
+		Target: domain.PatternTargetComment},
+}
+
+// migrateStockPatterns brings the stock patterns of a metadata database seeded
+// by an older configs/patterns.json up to date, since the classifier only
+// seeds an empty pattern table. Stock patterns that were edited since keep
+// their edits, deleted ones stay deleted, and the FULL_NAME pattern that also
+// matched any bare name column is narrowed to full names, with bare names
+// only matched in tables that hold people.
+func migrateStockPatterns(ctx context.Context, conn *sql.Conn) error {
+	var active int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(1) FROM classification_patterns WHERE is_active = 1").Scan(&active); err != nil {
+		return fmt.Errorf("failed to count patterns: %w", err)
+	}
+	if active == 0 {
+		// Seeded from configs/patterns.json on startup
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, u := range stockPatternUpgrades {
+		allowedJSON, err := marshalStringList(u.allowedDataTypes, "allowed data types")
+		if err != nil {
+			return err
+		}
+		disallowedJSON, err := marshalStringList(u.disallowedDataTypes, "disallowed data types")
+		if err != nil {
+			return err
+		}
+		var minLength any
+		if u.minLength > 0 {
+			minLength = u.minLength
+		}
+
+		_, err = conn.ExecContext(ctx, `
+			UPDATE classification_patterns
+			SET validator = ?, match_tokens = ?, allowed_data_types = ?, disallowed_data_types = ?, min_length = ?, updated_at = ?
+			WHERE pattern = ? AND pack IS NULL AND validator IS NULL AND match_tokens = 0
+				AND allowed_data_types IS NULL AND disallowed_data_types IS NULL AND min_length IS NULL AND max_length IS NULL
+		`, nullString(u.validator), boolToInt(u.matchTokens), allowedJSON, disallowedJSON, minLength, now, u.pattern)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade pattern %s: %w", u.pattern, err)
+		}
+	}
+
+	const (
+		broadFullName  = "(?i)^(full_?name|name|display_?name|complete_?name)$"
+		narrowFullName = "(?i)^(full_?name|display_?name|complete_?name)$"
+	)
+	var narrowed int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(1) FROM classification_patterns WHERE pattern = ?", narrowFullName).Scan(&narrowed); err != nil {
+		return fmt.Errorf("failed to check the full name pattern: %w", err)
+	}
+	if narrowed == 0 {
+		_, err := conn.ExecContext(ctx, "UPDATE classification_patterns SET pattern = ?, updated_at = ? WHERE pattern = ? AND pack IS NULL", narrowFullName, now, broadFullName)
+		if err != nil {
+			return fmt.Errorf("failed to narrow the full name pattern: %w", err)
+		}
+	}
+
+	for _, p := range stockPatternAdditions {
+		coColumnsJSON, err := marshalStringList(p.CoColumns, "co-columns")
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, `
+			INSERT IGNORE INTO classification_patterns (
+				id, information_type, pattern, description, priority, validator, target, match_tokens, pack, table_pattern, co_columns, context_boost,
+				allowed_data_types, disallowed_data_types, min_length, max_length, is_active, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, 0, NULL, ?, ?, ?, NULL, NULL, NULL, NULL, 1, ?, ?)
+		`, uuid.New().String(), p.InformationType, p.Pattern, p.Description, p.Priority, nullString(p.Validator), nullString(string(p.Target)),
+			nullString(p.TablePattern), coColumnsJSON, p.ContextBoost, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to add pattern %s: %w", p.Pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateScanFindingsNames makes the names in the findings tables
+// case-sensitive and widens them and the data type, so that tables differing
+// only by case, such as PostgreSQL "Users" and users, no longer collide on
+// uq_scan_tables_table and long SQLite names or PostgreSQL types fit.
+func migrateScanFindingsNames(ctx context.Context, conn *sql.Conn) error {
+	const name = "VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL"
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE scan_tables MODIFY schema_name %[1]s, MODIFY table_name %[1]s", name),
+		fmt.Sprintf("ALTER TABLE scan_columns MODIFY schema_name %[1]s, MODIFY table_name %[1]s, MODIFY column_name %[1]s, MODIFY data_type TEXT NOT NULL", name),
+	}
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to widen findings names: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateScanScopeOverridden records which scans ran with a scope given in the
+// scan request; existing scans are taken to have run with their connection's.
+func migrateScanScopeOverridden(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "scan_results", "scope_overridden", "TINYINT(1) NOT NULL DEFAULT 0")
+}
+
+func addColumnIfMissing(ctx context.Context, conn *sql.Conn, table, column, definition string) error {
+	var exists int
+	err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(1)
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+	`, table, column).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check column %s.%s: %w", table, column, err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+type RiskPolicyRepository struct {
+	db *sql.DB
+}
+
+func NewRiskPolicyRepository(db *sql.DB) *RiskPolicyRepository {
+	return &RiskPolicyRepository{db: db}
+}
+
+func (r *RiskPolicyRepository) Create(ctx context.Context, policy *domain.RiskPolicy) error {
+	query := `
+		INSERT INTO risk_policies (
+			id, name, description, type_weights, tier_weights, min_confidence, table_aggregation,
+			database_aggregation, medium_threshold, high_threshold, critical_threshold, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	typeWeightsJSON, err := marshalWeights(policy.TypeWeights, "type weights")
+	if err != nil {
+		return err
+	}
+	tierWeightsJSON, err := marshalWeights(policy.TierWeights, "tier weights")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		policy.ID.String(),
+		policy.Name,
+		policy.Description,
+		typeWeightsJSON,
+		tierWeightsJSON,
+		policy.MinConfidence,
+		policy.TableAggregation,
+		policy.DatabaseAggregation,
+		policy.Thresholds.Medium,
+		policy.Thresholds.High,
+		policy.Thresholds.Critical,
+		policy.CreatedAt.UTC(),
+		policy.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create risk policy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RiskPolicyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RiskPolicy, error) {
+	query := `
+		SELECT id, name, description, type_weights, tier_weights, min_confidence, table_aggregation,
+			database_aggregation, medium_threshold, high_threshold, critical_threshold, created_at, updated_at
+		FROM risk_policies
+		WHERE id = ?
+	`
+
+	row := r.db.QueryRowContext(ctx, query, id.String())
+	return scanRiskPolicy(row)
+}
+
+func (r *RiskPolicyRepository) GetAll(ctx context.Context) ([]*domain.RiskPolicy, error) {
+	query := `
+		SELECT id, name, description, type_weights, tier_weights, min_confidence, table_aggregation,
+			database_aggregation, medium_threshold, high_threshold, critical_threshold, created_at, updated_at
+		FROM risk_policies
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query risk policies: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*domain.RiskPolicy
+	for rows.Next() {
+		policy, err := scanRiskPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating risk policies: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *RiskPolicyRepository) Update(ctx context.Context, policy *domain.RiskPolicy) error {
+	query := `
+		UPDATE risk_policies
+		SET name = ?, description = ?, type_weights = ?, tier_weights = ?, min_confidence = ?,
+			table_aggregation = ?, database_aggregation = ?, medium_threshold = ?, high_threshold = ?,
+			critical_threshold = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	typeWeightsJSON, err := marshalWeights(policy.TypeWeights, "type weights")
+	if err != nil {
+		return err
+	}
+	tierWeightsJSON, err := marshalWeights(policy.TierWeights, "tier weights")
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		policy.Name,
+		policy.Description,
+		typeWeightsJSON,
+		tierWeightsJSON,
+		policy.MinConfidence,
+		policy.TableAggregation,
+		policy.DatabaseAggregation,
+		policy.Thresholds.Medium,
+		policy.Thresholds.High,
+		policy.Thresholds.Critical,
+		policy.UpdatedAt.UTC(),
+		policy.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update risk policy: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("risk policy not found")
+	}
+
+	return nil
+}
+
+func (r *RiskPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM risk_policies WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete risk policy: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("risk policy not found")
+	}
+
+	return nil
+}
+
+func scanRiskPolicy(scanner interface {
+	Scan(dest ...any) error
+}) (*domain.RiskPolicy, error) {
+	var (
+		idStr               string
+		name                string
+		description         sql.NullString
+		typeWeightsJSON     []byte
+		tierWeightsJSON     []byte
+		minConfidence       float64
+		tableAggregation    string
+		databaseAggregation string
+		thresholds          domain.RiskThresholds
+		createdAt           time.Time
+		updatedAt           time.Time
+	)
+
+	if err := scanner.Scan(
+		&idStr,
+		&name,
+		&description,
+		&typeWeightsJSON,
+		&tierWeightsJSON,
+		&minConfidence,
+		&tableAggregation,
+		&databaseAggregation,
+		&thresholds.Medium,
+		&thresholds.High,
+		&thresholds.Critical,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("risk policy not found")
+		}
+		return nil, fmt.Errorf("failed to scan risk policy: %w", err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid risk policy id: %w", err)
+	}
+
+	var typeWeights map[domain.InformationType]float64
+	if err := unmarshalWeights(typeWeightsJSON, &typeWeights, "type weights"); err != nil {
+		return nil, err
+	}
+	var tierWeights map[domain.SensitivityTier]float64
+	if err := unmarshalWeights(tierWeightsJSON, &tierWeights, "tier weights"); err != nil {
+		return nil, err
+	}
+
+	return &domain.RiskPolicy{
+		ID:                  id,
+		Name:                name,
+		Description:         stringOrEmpty(description),
+		TypeWeights:         typeWeights,
+		TierWeights:         tierWeights,
+		MinConfidence:       minConfidence,
+		TableAggregation:    domain.RiskAggregation(tableAggregation),
+		DatabaseAggregation: domain.RiskAggregation(databaseAggregation),
+		Thresholds:          thresholds,
+		CreatedAt:           createdAt,
+		UpdatedAt:           updatedAt,
+	}, nil
+}
+
+// marshalWeights stores a map of weights as JSON, or NULL when it is empty.
+func marshalWeights(weights any, name string) (any, error) {
+	data, err := json.Marshal(weights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	if string(data) == "null" || string(data) == "{}" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+func unmarshalWeights(data []byte, weights any, name string) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, weights); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"database-classifier/internal/domain"
+)
+
+// findingsBatchSize bounds the rows sent in a single multi-row INSERT.
+const findingsBatchSize = 200
+
+// writeFindings replaces the normalized scan_tables and scan_columns rows of a
+// scan with the tables and columns in result. The schemas_json blob remains
+// the source of truth for the ScanResult API; these rows exist for search and
+// reporting across scans.
+func writeFindings(ctx context.Context, tx *sql.Tx, result *domain.ScanResult) error {
+	scanID := result.ID.String()
+	databaseID := result.DatabaseID.String()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM scan_columns WHERE scan_id = ?", scanID); err != nil {
+		return fmt.Errorf("failed to clear scan columns: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM scan_tables WHERE scan_id = ?", scanID); err != nil {
+		return fmt.Errorf("failed to clear scan tables: %w", err)
+	}
+
+	var tableRows, columnRows [][]any
+	for _, schema := range result.Schemas {
+		for _, table := range schema.Tables {
+			classified := 0
+			for _, column := range table.Columns {
+				if column.InformationType != domain.InfoTypeNA {
+					classified++
+				}
+
+				matchedPatterns, err := json.Marshal(column.MatchedPatterns)
+				if err != nil {
+					return fmt.Errorf("failed to marshal matched patterns: %w", err)
+				}
+
+				columnRows = append(columnRows, []any{
+					scanID,
+					databaseID,
+					schema.SchemaName,
+					table.TableName,
+					column.ColumnName,
+					column.DataType,
+					column.InformationType,
+					column.ConfidenceScore,
+					matchedPatterns,
+					boolToInt(column.IsNullable),
+					column.SampleSize,
+					column.MatchedSamples,
+				})
+			}
+
+			tableRows = append(tableRows, []any{
+				scanID,
+				databaseID,
+				schema.SchemaName,
+				table.TableName,
+				len(table.Columns),
+				classified,
+			})
+		}
+	}
+
+	if err := insertRows(ctx, tx, "scan_tables", []string{
+		"scan_id", "database_id", "schema_name", "table_name", "total_columns", "classified_columns",
+	}, tableRows); err != nil {
+		return fmt.Errorf("failed to insert scan tables: %w", err)
+	}
+
+	if err := insertRows(ctx, tx, "scan_columns", []string{
+		"scan_id", "database_id", "schema_name", "table_name", "column_name", "data_type",
+		"information_type", "confidence_score", "matched_patterns", "is_nullable", "sample_size", "matched_samples",
+	}, columnRows); err != nil {
+		return fmt.Errorf("failed to insert scan columns: %w", err)
+	}
+
+	return nil
+}
+
+func insertRows(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	rowPlaceholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	for start := 0; start < len(rows); start += findingsBatchSize {
+		end := start + findingsBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		batch := rows[start:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]any, 0, len(batch)*len(columns))
+		for i, row := range batch {
+			placeholders[i] = rowPlaceholder
+			args = append(args, row...)
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
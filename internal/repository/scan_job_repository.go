@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+const mysqlErrDuplicateEntry = 1062
+
+type ScanJobRepository struct {
+	db *sql.DB
+}
+
+func NewScanJobRepository(db *sql.DB) *ScanJobRepository {
+	return &ScanJobRepository{db: db}
+}
+
+func (r *ScanJobRepository) Enqueue(ctx context.Context, job *domain.ScanJob) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now().UTC()
+	}
+
+	query := `
+		INSERT INTO scan_jobs (scan_id, database_id, target, enqueued_at, claimed_by, claimed_at)
+		VALUES (?, ?, ?, ?, NULL, NULL)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, job.ScanID.String(), job.DatabaseID.String(), job.Target, job.EnqueuedAt.UTC())
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return domain.ErrScanInProgress
+		}
+		return fmt.Errorf("failed to enqueue scan job: %w", err)
+	}
+
+	return nil
+}
+
+// Requeue puts a job back in the queue as unclaimed, inserting it if the row
+// no longer exists. The original enqueue time is kept so it is not starved.
+// It returns domain.ErrScanInProgress, leaving the queue untouched, when
+// another scan of the same database is queued or claimed.
+func (r *ScanJobRepository) Requeue(ctx context.Context, job *domain.ScanJob) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now().UTC()
+	}
+
+	res, err := r.db.ExecContext(ctx, "UPDATE scan_jobs SET claimed_by = NULL, claimed_at = NULL WHERE scan_id = ?", job.ScanID.String())
+	if err != nil {
+		return fmt.Errorf("failed to requeue scan job: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	// Either the row is gone or it was already unclaimed, which the
+	// duplicate scan_id tells apart from another scan of the database
+	err = r.Enqueue(ctx, job)
+	if !errors.Is(err, domain.ErrScanInProgress) {
+		return err
+	}
+	var queued int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(1) FROM scan_jobs WHERE scan_id = ?", job.ScanID.String()).Scan(&queued); err != nil {
+		return fmt.Errorf("failed to check scan job: %w", err)
+	}
+	if queued > 0 {
+		return nil
+	}
+	return domain.ErrScanInProgress
+}
+
+// Claim assigns the oldest unclaimed job whose target has spare capacity to
+// workerID. The whole queue is locked for the duration of the decision so that
+// the global and per-target limits hold across every API replica. It returns
+// nil when no job can be claimed right now.
+func (r *ScanJobRepository) Claim(ctx context.Context, workerID string, maxConcurrent, maxPerTarget int) (*domain.ScanJob, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin claim transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT scan_id, database_id, target, enqueued_at, claimed_by, claimed_at
+		FROM scan_jobs
+		ORDER BY enqueued_at ASC
+		FOR UPDATE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock scan jobs: %w", err)
+	}
+
+	var jobs []*domain.ScanJob
+	for rows.Next() {
+		job, err := scanScanJob(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("error iterating scan jobs: %w", err)
+	}
+	rows.Close()
+
+	claimed := 0
+	perTarget := make(map[string]int)
+	for _, job := range jobs {
+		if job.ClaimedBy != "" {
+			claimed++
+			perTarget[job.Target]++
+		}
+	}
+	if claimed >= maxConcurrent {
+		return nil, nil
+	}
+
+	var next *domain.ScanJob
+	for _, job := range jobs {
+		if job.ClaimedBy == "" && perTarget[job.Target] < maxPerTarget {
+			next = job
+			break
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	_, err = tx.ExecContext(ctx, "UPDATE scan_jobs SET claimed_by = ?, claimed_at = ? WHERE scan_id = ?", workerID, now, next.ScanID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim scan job: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scan job claim: %w", err)
+	}
+
+	next.ClaimedBy = workerID
+	next.ClaimedAt = &now
+	return next, nil
+}
+
+func (r *ScanJobRepository) GetAll(ctx context.Context) ([]*domain.ScanJob, error) {
+	query := `
+		SELECT scan_id, database_id, target, enqueued_at, claimed_by, claimed_at
+		FROM scan_jobs
+		ORDER BY enqueued_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scan jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.ScanJob
+	for rows.Next() {
+		job, err := scanScanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scan jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetQueuePosition returns the 1-based position of an unclaimed job among the
+// unclaimed jobs, or 0 if the job is claimed or not queued.
+func (r *ScanJobRepository) GetQueuePosition(ctx context.Context, scanID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(1)
+		FROM scan_jobs ahead
+		JOIN scan_jobs job ON job.scan_id = ? AND job.claimed_by IS NULL
+		WHERE ahead.claimed_by IS NULL
+			AND (ahead.enqueued_at < job.enqueued_at
+				OR (ahead.enqueued_at = job.enqueued_at AND ahead.scan_id <= job.scan_id))
+	`
+
+	var position int
+	if err := r.db.QueryRowContext(ctx, query, scanID.String()).Scan(&position); err != nil {
+		return 0, fmt.Errorf("failed to get queue position: %w", err)
+	}
+
+	return position, nil
+}
+
+func (r *ScanJobRepository) Delete(ctx context.Context, scanID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM scan_jobs WHERE scan_id = ?", scanID.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete scan job: %w", err)
+	}
+	return nil
+}
+
+func scanScanJob(scanner interface {
+	Scan(dest ...any) error
+}) (*domain.ScanJob, error) {
+	var (
+		scanIDStr  string
+		dbIDStr    string
+		target     string
+		enqueuedAt time.Time
+		claimedBy  sql.NullString
+		claimedRaw sql.NullTime
+	)
+
+	if err := scanner.Scan(&scanIDStr, &dbIDStr, &target, &enqueuedAt, &claimedBy, &claimedRaw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("scan job not found")
+		}
+		return nil, fmt.Errorf("failed to scan job: %w", err)
+	}
+
+	scanID, err := uuid.Parse(scanIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan id: %w", err)
+	}
+
+	dbID, err := uuid.Parse(dbIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database id: %w", err)
+	}
+
+	var claimedAt *time.Time
+	if claimedRaw.Valid {
+		v := claimedRaw.Time
+		claimedAt = &v
+	}
+
+	return &domain.ScanJob{
+		ScanID:     scanID,
+		DatabaseID: dbID,
+		Target:     target,
+		EnqueuedAt: enqueuedAt,
+		ClaimedBy:  stringOrEmpty(claimedBy),
+		ClaimedAt:  claimedAt,
+	}, nil
+}
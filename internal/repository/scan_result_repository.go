@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -39,10 +40,15 @@ func (r *ScanResultRepository) Create(ctx context.Context, result *domain.ScanRe
 		return fmt.Errorf("failed to marshal summary: %w", err)
 	}
 
+	scopeJSON, err := marshalScope(result.Scope)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO scan_results (
-			id, database_id, started_at, completed_at, status, error_message, schemas_json, summary_json
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, database_id, started_at, completed_at, status, error_message, schemas_json, summary_json, scope_json, incremental, scope_overridden
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err = r.db.ExecContext(
@@ -56,6 +62,9 @@ func (r *ScanResultRepository) Create(ctx context.Context, result *domain.ScanRe
 		result.ErrorMessage,
 		schemasJSON,
 		summaryJSON,
+		scopeJSON,
+		boolToInt(result.Incremental),
+		boolToInt(result.ScopeOverridden),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create scan result: %w", err)
@@ -66,7 +75,8 @@ func (r *ScanResultRepository) Create(ctx context.Context, result *domain.ScanRe
 
 func (r *ScanResultRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScanResult, error) {
 	query := `
-		SELECT id, database_id, started_at, completed_at, status, error_message, schemas_json, summary_json
+		SELECT id, database_id, started_at, completed_at, heartbeat_at, status, error_message, schemas_json, summary_json, scope_json, progress_json, errors_json,
+			incremental, base_scan_id, scope_overridden
 		FROM scan_results
 		WHERE id = ?
 	`
@@ -77,7 +87,8 @@ func (r *ScanResultRepository) GetByID(ctx context.Context, id uuid.UUID) (*doma
 
 func (r *ScanResultRepository) GetByDatabaseID(ctx context.Context, databaseID uuid.UUID, limit int) ([]*domain.ScanResult, error) {
 	query := `
-		SELECT id, database_id, started_at, completed_at, status, error_message, schemas_json, summary_json
+		SELECT id, database_id, started_at, completed_at, heartbeat_at, status, error_message, schemas_json, summary_json, scope_json, progress_json, errors_json,
+			incremental, base_scan_id, scope_overridden
 		FROM scan_results
 		WHERE database_id = ?
 		ORDER BY started_at DESC
@@ -106,16 +117,19 @@ func (r *ScanResultRepository) GetByDatabaseID(ctx context.Context, databaseID u
 	return results, nil
 }
 
+// GetLatestByDatabaseID returns the latest completed scan of a database that
+// ran with the connection's own scope.
 func (r *ScanResultRepository) GetLatestByDatabaseID(ctx context.Context, databaseID uuid.UUID) (*domain.ScanResult, error) {
 	query := `
-		SELECT id, database_id, started_at, completed_at, status, error_message, schemas_json, summary_json
+		SELECT id, database_id, started_at, completed_at, heartbeat_at, status, error_message, schemas_json, summary_json, scope_json, progress_json, errors_json,
+			incremental, base_scan_id, scope_overridden
 		FROM scan_results
-		WHERE database_id = ? AND status = ?
-		ORDER BY started_at DESC
+		WHERE database_id = ? AND status IN (?, ?) AND scope_overridden = 0
+		ORDER BY started_at DESC, id DESC
 		LIMIT 1
 	`
 
-	row := r.db.QueryRowContext(ctx, query, databaseID.String(), domain.ScanStatusCompleted)
+	row := r.db.QueryRowContext(ctx, query, databaseID.String(), domain.ScanStatusCompleted, domain.ScanStatusCompletedWithErrors)
 	return scanScanResult(row)
 }
 
@@ -130,15 +144,26 @@ func (r *ScanResultRepository) Update(ctx context.Context, result *domain.ScanRe
 		return fmt.Errorf("failed to marshal summary: %w", err)
 	}
 
+	progressJSON, err := marshalProgress(result.Progress)
+	if err != nil {
+		return err
+	}
+
+	errorsJSON, err := marshalScanErrors(result.Errors)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE scan_results
 		SET database_id = ?, started_at = ?, completed_at = ?, status = ?, error_message = ?,
-			schemas_json = ?, summary_json = ?
+			schemas_json = ?, summary_json = ?, progress_json = ?, errors_json = ?, base_scan_id = ?
 		WHERE id = ?
 	`
 
-	res, err := r.db.ExecContext(
+	updated, err := r.save(
 		ctx,
+		result,
 		query,
 		result.DatabaseID.String(),
 		result.StartedAt.UTC(),
@@ -147,18 +172,16 @@ func (r *ScanResultRepository) Update(ctx context.Context, result *domain.ScanRe
 		result.ErrorMessage,
 		schemasJSON,
 		summaryJSON,
+		progressJSON,
+		errorsJSON,
+		nullUUID(result.BaseScanID),
 		result.ID.String(),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update scan result: %w", err)
+		return err
 	}
-
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to read affected rows: %w", err)
-	}
-	if rows == 0 {
-		return fmt.Errorf("scan result not found")
+	if !updated {
+		return domain.ErrScanNotFound
 	}
 
 	return nil
@@ -175,7 +198,7 @@ func (r *ScanResultRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("failed to read affected rows: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("scan result not found")
+		return domain.ErrScanNotFound
 	}
 
 	return nil
@@ -183,7 +206,7 @@ func (r *ScanResultRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *ScanResultRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ScanStatus, errorMessage string) error {
 	var completedAt any
-	if status == domain.ScanStatusCompleted || status == domain.ScanStatusFailed || status == domain.ScanStatusCancelled {
+	if status == domain.ScanStatusCompleted || status == domain.ScanStatusCompletedWithErrors || status == domain.ScanStatusFailed || status == domain.ScanStatusCancelled {
 		completedAt = time.Now().UTC()
 	}
 
@@ -203,15 +226,234 @@ func (r *ScanResultRepository) UpdateStatus(ctx context.Context, id uuid.UUID, s
 		return fmt.Errorf("failed to read affected rows: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("scan result not found")
+		return domain.ErrScanNotFound
+	}
+
+	return nil
+}
+
+// UpdateIfStatus persists result only while the stored row still has the
+// expected status, so a concurrent cancellation is never overwritten.
+func (r *ScanResultRepository) UpdateIfStatus(ctx context.Context, result *domain.ScanResult, expected domain.ScanStatus) (bool, error) {
+	schemasJSON, err := json.Marshal(result.Schemas)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal schemas: %w", err)
+	}
+
+	summaryJSON, err := json.Marshal(result.Summary)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal summary: %w", err)
+	}
+
+	progressJSON, err := marshalProgress(result.Progress)
+	if err != nil {
+		return false, err
+	}
+
+	errorsJSON, err := marshalScanErrors(result.Errors)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		UPDATE scan_results
+		SET database_id = ?, started_at = ?, completed_at = ?, status = ?, error_message = ?,
+			schemas_json = ?, summary_json = ?, progress_json = ?, errors_json = ?, base_scan_id = ?
+		WHERE id = ? AND status = ?
+	`
+
+	return r.save(
+		ctx,
+		result,
+		query,
+		result.DatabaseID.String(),
+		result.StartedAt.UTC(),
+		nullTime(result.CompletedAt),
+		result.Status,
+		result.ErrorMessage,
+		schemasJSON,
+		summaryJSON,
+		progressJSON,
+		errorsJSON,
+		nullUUID(result.BaseScanID),
+		result.ID.String(),
+		expected,
+	)
+}
+
+// save runs an UPDATE of a scan result and, when it leaves the scan completed,
+// rewrites its normalized findings in the same transaction.
+func (r *ScanResultRepository) save(ctx context.Context, result *domain.ScanResult, query string, args ...any) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update scan result: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if result.Status == domain.ScanStatusCompleted || result.Status == domain.ScanStatusCompletedWithErrors {
+		if err := writeFindings(ctx, tx, result); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit scan result: %w", err)
+	}
+
+	return true, nil
+}
+
+// TransitionStatus moves a scan to status only if its current status is one of
+// from. It reports whether the transition happened.
+func (r *ScanResultRepository) TransitionStatus(ctx context.Context, id uuid.UUID, status domain.ScanStatus, errorMessage string, from ...domain.ScanStatus) (bool, error) {
+	if len(from) == 0 {
+		return false, fmt.Errorf("at least one source status is required")
+	}
+
+	var completedAt any
+	if status == domain.ScanStatusCompleted || status == domain.ScanStatusCompletedWithErrors || status == domain.ScanStatusFailed || status == domain.ScanStatusCancelled {
+		completedAt = time.Now().UTC()
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(from)), ", ")
+	query := fmt.Sprintf(`
+		UPDATE scan_results
+		SET status = ?, completed_at = ?, error_message = ?
+		WHERE id = ? AND status IN (%s)
+	`, placeholders)
+
+	args := []any{status, completedAt, errorMessage, id.String()}
+	for _, f := range from {
+		args = append(args, f)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to transition scan status: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *ScanResultRepository) GetStatus(ctx context.Context, id uuid.UUID) (domain.ScanStatus, error) {
+	var status string
+	err := r.db.QueryRowContext(ctx, "SELECT status FROM scan_results WHERE id = ?", id.String()).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrScanNotFound
+		}
+		return "", fmt.Errorf("failed to get scan status: %w", err)
+	}
+	return domain.ScanStatus(status), nil
+}
+
+// UpdateProgress records the progress of a running scan.
+func (r *ScanResultRepository) UpdateProgress(ctx context.Context, id uuid.UUID, progress *domain.ScanProgress) error {
+	progressJSON, err := marshalProgress(progress)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE scan_results SET progress_json = ? WHERE id = ?", progressJSON, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to record scan progress: %w", err)
+	}
+	return nil
+}
+
+// UpdateRisk stores the risk assessment carried by the schemas and summary of
+// a scan and leaves the rest of it as it is. Rescoring a scan often leaves it
+// unchanged, so unlike Update it does not fail when no row changes.
+func (r *ScanResultRepository) UpdateRisk(ctx context.Context, result *domain.ScanResult) error {
+	schemasJSON, err := json.Marshal(result.Schemas)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schemas: %w", err)
 	}
 
+	summaryJSON, err := json.Marshal(result.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE scan_results SET schemas_json = ?, summary_json = ? WHERE id = ?", schemasJSON, summaryJSON, result.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to update scan risk: %w", err)
+	}
+	return nil
+}
+
+func (r *ScanResultRepository) Heartbeat(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE scan_results SET heartbeat_at = ? WHERE id = ?", time.Now().UTC(), id.String())
+	if err != nil {
+		return fmt.Errorf("failed to record scan heartbeat: %w", err)
+	}
 	return nil
 }
 
+// ReclaimStale moves a pending or running scan whose last heartbeat (or start
+// time, if it never reported one) is older than staleBefore to status. Only one
+// caller can reclaim a given scan because the staleness check is part of the
+// UPDATE and the reclaim refreshes the heartbeat.
+func (r *ScanResultRepository) ReclaimStale(ctx context.Context, id uuid.UUID, staleBefore time.Time, status domain.ScanStatus, errorMessage string) (bool, error) {
+	now := time.Now().UTC()
+
+	var completedAt any
+	if status == domain.ScanStatusCompleted || status == domain.ScanStatusCompletedWithErrors || status == domain.ScanStatusFailed || status == domain.ScanStatusCancelled {
+		completedAt = now
+	}
+
+	query := `
+		UPDATE scan_results
+		SET status = ?, completed_at = ?, error_message = ?, heartbeat_at = ?
+		WHERE id = ? AND status IN (?, ?) AND COALESCE(heartbeat_at, started_at) < ?
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		status,
+		completedAt,
+		errorMessage,
+		now,
+		id.String(),
+		domain.ScanStatusPending,
+		domain.ScanStatusRunning,
+		staleBefore.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim stale scan: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
 func (r *ScanResultRepository) GetRunningScans(ctx context.Context) ([]*domain.ScanResult, error) {
 	query := `
-		SELECT id, database_id, started_at, completed_at, status, error_message, schemas_json, summary_json
+		SELECT id, database_id, started_at, completed_at, heartbeat_at, status, error_message, schemas_json, summary_json, scope_json, progress_json, errors_json,
+			incremental, base_scan_id, scope_overridden
 		FROM scan_results
 		WHERE status IN (?, ?)
 		ORDER BY started_at ASC
@@ -247,15 +489,22 @@ func scanScanResult(scanner interface {
 		dbIDStr      string
 		startedAt    time.Time
 		completedRaw sql.NullTime
+		heartbeatRaw sql.NullTime
 		status       string
 		errorMessage sql.NullString
 		schemasJSON  []byte
 		summaryJSON  []byte
+		scopeJSON    []byte
+		progressJSON []byte
+		errorsJSON   []byte
+		incremental  int
+		baseScanRaw  sql.NullString
+		overridden   int
 	)
 
-	if err := scanner.Scan(&idStr, &dbIDStr, &startedAt, &completedRaw, &status, &errorMessage, &schemasJSON, &summaryJSON); err != nil {
+	if err := scanner.Scan(&idStr, &dbIDStr, &startedAt, &completedRaw, &heartbeatRaw, &status, &errorMessage, &schemasJSON, &summaryJSON, &scopeJSON, &progressJSON, &errorsJSON, &incremental, &baseScanRaw, &overridden); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("scan result not found")
+			return nil, domain.ErrScanNotFound
 		}
 		return nil, fmt.Errorf("failed to scan result: %w", err)
 	}
@@ -284,22 +533,86 @@ func scanScanResult(scanner interface {
 		}
 	}
 
+	scope, err := unmarshalScope(scopeJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var progress *domain.ScanProgress
+	if len(progressJSON) > 0 {
+		progress = &domain.ScanProgress{}
+		if err := json.Unmarshal(progressJSON, progress); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal progress: %w", err)
+		}
+	}
+
+	var scanErrors []domain.ScanError
+	if len(errorsJSON) > 0 {
+		if err := json.Unmarshal(errorsJSON, &scanErrors); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal scan errors: %w", err)
+		}
+	}
+
+	var baseScanID *uuid.UUID
+	if baseScanRaw.Valid {
+		v, err := uuid.Parse(baseScanRaw.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base scan id: %w", err)
+		}
+		baseScanID = &v
+	}
+
 	var completedAt *time.Time
 	if completedRaw.Valid {
 		v := completedRaw.Time
 		completedAt = &v
 	}
 
+	var heartbeatAt *time.Time
+	if heartbeatRaw.Valid {
+		v := heartbeatRaw.Time
+		heartbeatAt = &v
+	}
+
 	result := &domain.ScanResult{
-		ID:           id,
-		DatabaseID:   dbID,
-		StartedAt:    startedAt,
-		CompletedAt:  completedAt,
-		Status:       domain.ScanStatus(status),
-		ErrorMessage: stringOrEmpty(errorMessage),
-		Schemas:      schemas,
-		Summary:      summary,
+		ID:              id,
+		DatabaseID:      dbID,
+		StartedAt:       startedAt,
+		CompletedAt:     completedAt,
+		HeartbeatAt:     heartbeatAt,
+		Status:          domain.ScanStatus(status),
+		ErrorMessage:    stringOrEmpty(errorMessage),
+		Schemas:         schemas,
+		Summary:         summary,
+		Scope:           scope,
+		Progress:        progress,
+		Errors:          scanErrors,
+		Incremental:     incremental == 1,
+		BaseScanID:      baseScanID,
+		ScopeOverridden: overridden == 1,
 	}
 
 	return result, nil
 }
+
+func marshalProgress(progress *domain.ScanProgress) (any, error) {
+	if progress == nil {
+		return nil, nil
+	}
+	progressJSON, err := json.Marshal(progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal progress: %w", err)
+	}
+	return progressJSON, nil
+}
+
+func marshalScanErrors(scanErrors []domain.ScanError) (any, error) {
+	if len(scanErrors) == 0 {
+		return nil, nil
+	}
+	errorsJSON, err := json.Marshal(scanErrors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scan errors: %w", err)
+	}
+	return errorsJSON, nil
+}
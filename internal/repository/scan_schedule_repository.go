@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+type ScanScheduleRepository struct {
+	db *sql.DB
+}
+
+func NewScanScheduleRepository(db *sql.DB) *ScanScheduleRepository {
+	return &ScanScheduleRepository{db: db}
+}
+
+const scanScheduleColumns = `id, database_id, cron_expression, interval_seconds, timezone, is_paused,
+			incremental, next_run_at, last_run_at, last_scan_id, created_at, updated_at`
+
+func (r *ScanScheduleRepository) Create(ctx context.Context, schedule *domain.ScanSchedule) error {
+	query := `
+		INSERT INTO scan_schedules (` + scanScheduleColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		schedule.ID.String(),
+		schedule.DatabaseID.String(),
+		nullString(schedule.CronExpression),
+		nullInt(schedule.IntervalSeconds),
+		schedule.Timezone,
+		boolToInt(schedule.IsPaused),
+		boolToInt(schedule.Incremental),
+		schedule.NextRunAt.UTC(),
+		nullTime(schedule.LastRunAt),
+		nullUUID(schedule.LastScanID),
+		schedule.CreatedAt.UTC(),
+		schedule.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scan schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScanScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScanSchedule, error) {
+	query := `
+		SELECT ` + scanScheduleColumns + `
+		FROM scan_schedules
+		WHERE id = ?
+	`
+
+	row := r.db.QueryRowContext(ctx, query, id.String())
+	return scanScanSchedule(row)
+}
+
+func (r *ScanScheduleRepository) GetAll(ctx context.Context) ([]*domain.ScanSchedule, error) {
+	query := `
+		SELECT ` + scanScheduleColumns + `
+		FROM scan_schedules
+		ORDER BY created_at DESC
+	`
+
+	return r.query(ctx, query)
+}
+
+func (r *ScanScheduleRepository) GetByDatabaseID(ctx context.Context, databaseID uuid.UUID) ([]*domain.ScanSchedule, error) {
+	query := `
+		SELECT ` + scanScheduleColumns + `
+		FROM scan_schedules
+		WHERE database_id = ?
+		ORDER BY created_at DESC
+	`
+
+	return r.query(ctx, query, databaseID.String())
+}
+
+func (r *ScanScheduleRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*domain.ScanSchedule, error) {
+	query := `
+		SELECT ` + scanScheduleColumns + `
+		FROM scan_schedules
+		WHERE is_paused = 0 AND next_run_at <= ?
+		ORDER BY next_run_at ASC
+		LIMIT ?
+	`
+
+	return r.query(ctx, query, now.UTC(), limit)
+}
+
+func (r *ScanScheduleRepository) Update(ctx context.Context, schedule *domain.ScanSchedule) error {
+	query := `
+		UPDATE scan_schedules
+		SET cron_expression = ?, interval_seconds = ?, timezone = ?, is_paused = ?, incremental = ?,
+			next_run_at = ?, last_run_at = ?, last_scan_id = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		nullString(schedule.CronExpression),
+		nullInt(schedule.IntervalSeconds),
+		schedule.Timezone,
+		boolToInt(schedule.IsPaused),
+		boolToInt(schedule.Incremental),
+		schedule.NextRunAt.UTC(),
+		nullTime(schedule.LastRunAt),
+		nullUUID(schedule.LastScanID),
+		schedule.UpdatedAt.UTC(),
+		schedule.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update scan schedule: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("scan schedule not found")
+	}
+
+	return nil
+}
+
+// MarkFired advances a schedule to nextRunAt only if it is still due at
+// expectedNextRunAt and not paused, so a firing is claimed exactly once even
+// if two schedulers briefly overlap.
+func (r *ScanScheduleRepository) MarkFired(ctx context.Context, id uuid.UUID, expectedNextRunAt, nextRunAt, firedAt time.Time) (bool, error) {
+	query := `
+		UPDATE scan_schedules
+		SET next_run_at = ?, last_run_at = ?, updated_at = ?
+		WHERE id = ? AND next_run_at = ? AND is_paused = 0
+	`
+
+	res, err := r.db.ExecContext(ctx, query, nextRunAt.UTC(), firedAt.UTC(), firedAt.UTC(), id.String(), expectedNextRunAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to mark scan schedule as fired: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *ScanScheduleRepository) SetLastScan(ctx context.Context, id uuid.UUID, scanID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE scan_schedules SET last_scan_id = ? WHERE id = ?", scanID.String(), id.String())
+	if err != nil {
+		return fmt.Errorf("failed to record last scheduled scan: %w", err)
+	}
+	return nil
+}
+
+func (r *ScanScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM scan_schedules WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete scan schedule: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("scan schedule not found")
+	}
+
+	return nil
+}
+
+func (r *ScanScheduleRepository) query(ctx context.Context, query string, args ...any) ([]*domain.ScanSchedule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scan schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*domain.ScanSchedule
+	for rows.Next() {
+		schedule, err := scanScanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scan schedules: %w", err)
+	}
+
+	return result, nil
+}
+
+func scanScanSchedule(scanner interface {
+	Scan(dest ...any) error
+}) (*domain.ScanSchedule, error) {
+	var (
+		idStr          string
+		dbIDStr        string
+		cronExpression sql.NullString
+		interval       sql.NullInt64
+		timezone       string
+		isPaused       int
+		incremental    int
+		nextRunAt      time.Time
+		lastRunRaw     sql.NullTime
+		lastScanRaw    sql.NullString
+		createdAt      time.Time
+		updatedAt      time.Time
+	)
+
+	if err := scanner.Scan(
+		&idStr,
+		&dbIDStr,
+		&cronExpression,
+		&interval,
+		&timezone,
+		&isPaused,
+		&incremental,
+		&nextRunAt,
+		&lastRunRaw,
+		&lastScanRaw,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("scan schedule not found")
+		}
+		return nil, fmt.Errorf("failed to scan schedule: %w", err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule id: %w", err)
+	}
+
+	dbID, err := uuid.Parse(dbIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database id: %w", err)
+	}
+
+	var lastRunAt *time.Time
+	if lastRunRaw.Valid {
+		v := lastRunRaw.Time
+		lastRunAt = &v
+	}
+
+	var lastScanID *uuid.UUID
+	if lastScanRaw.Valid {
+		v, err := uuid.Parse(lastScanRaw.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last scan id: %w", err)
+		}
+		lastScanID = &v
+	}
+
+	return &domain.ScanSchedule{
+		ID:              id,
+		DatabaseID:      dbID,
+		CronExpression:  stringOrEmpty(cronExpression),
+		IntervalSeconds: int(interval.Int64),
+		Timezone:        timezone,
+		IsPaused:        isPaused == 1,
+		Incremental:     incremental == 1,
+		NextRunAt:       nextRunAt,
+		LastRunAt:       lastRunAt,
+		LastScanID:      lastScanID,
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+	"database-classifier/pkg/classifier"
+)
+
+// ensureAbbreviations seeds the abbreviation dictionary from
+// defaultAbbreviationsPath while it is empty.
+func (s *ClassificationService) ensureAbbreviations(ctx context.Context, defaultAbbreviationsPath string) error {
+	abbreviations, err := s.abbreviationRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load abbreviations: %w", err)
+	}
+	if len(abbreviations) > 0 || defaultAbbreviationsPath == "" {
+		return nil
+	}
+
+	seeds, err := loadAbbreviationSeeds(defaultAbbreviationsPath)
+	if err != nil {
+		return err
+	}
+	for _, seed := range seeds {
+		abbreviation, expansion, err := normalizeAbbreviation(seed.Abbreviation, seed.Expansion)
+		if err != nil {
+			return fmt.Errorf("invalid seed abbreviation %s: %w", seed.Abbreviation, err)
+		}
+
+		exists, err := s.abbreviationRepo.ExistsByAbbreviation(ctx, abbreviation)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		now := time.Now().UTC()
+		model := &domain.Abbreviation{
+			ID:           uuid.New(),
+			Abbreviation: abbreviation,
+			Expansion:    expansion,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := s.abbreviationRepo.Create(ctx, model); err != nil {
+			return fmt.Errorf("failed to seed abbreviation %s: %w", abbreviation, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ClassificationService) CreateAbbreviation(ctx context.Context, req *domain.CreateAbbreviationRequest) (uuid.UUID, error) {
+	abbreviation, expansion, err := normalizeAbbreviation(req.Abbreviation, req.Expansion)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	exists, err := s.abbreviationRepo.ExistsByAbbreviation(ctx, abbreviation)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if exists {
+		return uuid.Nil, fmt.Errorf("abbreviation already exists")
+	}
+
+	id := uuid.New()
+	now := time.Now().UTC()
+	model := &domain.Abbreviation{
+		ID:           id,
+		Abbreviation: abbreviation,
+		Expansion:    expansion,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.abbreviationRepo.Create(ctx, model); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create abbreviation: %w", err)
+	}
+
+	if err := s.reloadMatcher(ctx); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+func (s *ClassificationService) GetAllAbbreviations(ctx context.Context) ([]*domain.Abbreviation, error) {
+	return s.abbreviationRepo.GetAll(ctx)
+}
+
+func (s *ClassificationService) UpdateAbbreviation(ctx context.Context, id uuid.UUID, req *domain.CreateAbbreviationRequest) error {
+	abbreviation, expansion, err := normalizeAbbreviation(req.Abbreviation, req.Expansion)
+	if err != nil {
+		return err
+	}
+
+	model, err := s.abbreviationRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	model.Abbreviation = abbreviation
+	model.Expansion = expansion
+	model.UpdatedAt = time.Now().UTC()
+
+	if err := s.abbreviationRepo.Update(ctx, model); err != nil {
+		return fmt.Errorf("failed to update abbreviation: %w", err)
+	}
+
+	return s.reloadMatcher(ctx)
+}
+
+func (s *ClassificationService) DeleteAbbreviation(ctx context.Context, id uuid.UUID) error {
+	if err := s.abbreviationRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return s.reloadMatcher(ctx)
+}
+
+// normalizeAbbreviation checks that abbreviation is a single column name token
+// and returns it with expansion in the form tokens are matched in, e.g. DOB
+// and "Date of Birth" as dob and date_of_birth.
+func normalizeAbbreviation(abbreviation, expansion string) (string, string, error) {
+	tokens := classifier.Tokenize(abbreviation)
+	if len(tokens) != 1 {
+		return "", "", fmt.Errorf("abbreviation %q must be a single token", abbreviation)
+	}
+
+	expanded := classifier.Tokenize(expansion)
+	if len(expanded) == 0 {
+		return "", "", fmt.Errorf("expansion %q has no tokens", expansion)
+	}
+
+	return tokens[0], strings.Join(expanded, "_"), nil
+}
+
+type abbreviationSeed struct {
+	Abbreviation string `json:"abbreviation"`
+	Expansion    string `json:"expansion"`
+}
+
+func loadAbbreviationSeeds(path string) ([]abbreviationSeed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read abbreviations file: %w", err)
+	}
+
+	var seeds []abbreviationSeed
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return nil, fmt.Errorf("failed to parse abbreviations file: %w", err)
+	}
+
+	return seeds, nil
+}
@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,16 +19,36 @@ import (
 )
 
 type ClassificationService struct {
-	repo     domain.ClassificationPatternRepository
-	mu       sync.RWMutex
-	matcher  *classifier.Classifier
+	repo             domain.ClassificationPatternRepository
+	abbreviationRepo domain.AbbreviationRepository
+	infoTypeRepo     domain.InformationTypeRepository
+	packsDir         string
+	mu               sync.RWMutex
+	matcher          *classifier.Classifier
+	infoTypes        map[domain.InformationType]*domain.InformationTypeDefinition
+	// matcherVersion and infoTypesVersion hash what matcher and infoTypes
+	// were loaded from.
+	matcherVersion   string
+	infoTypesVersion string
 }
 
-func NewClassificationService(ctx context.Context, repo domain.ClassificationPatternRepository, defaultPatternsPath string) (*ClassificationService, error) {
-	svc := &ClassificationService{repo: repo}
+func NewClassificationService(ctx context.Context, repo domain.ClassificationPatternRepository, abbreviationRepo domain.AbbreviationRepository, infoTypeRepo domain.InformationTypeRepository, defaultPatternsPath, defaultAbbreviationsPath, defaultInformationTypesPath, packsDir string) (*ClassificationService, error) {
+	svc := &ClassificationService{repo: repo, abbreviationRepo: abbreviationRepo, infoTypeRepo: infoTypeRepo, packsDir: packsDir}
 	if err := svc.ensurePatterns(ctx, defaultPatternsPath); err != nil {
 		return nil, err
 	}
+	if err := svc.ensureAbbreviations(ctx, defaultAbbreviationsPath); err != nil {
+		return nil, err
+	}
+	if err := svc.ensureInformationTypes(ctx, defaultInformationTypesPath); err != nil {
+		return nil, err
+	}
+	if err := svc.reloadMatcher(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load classifier: %w", err)
+	}
+	if err := svc.reloadInformationTypes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load information types: %w", err)
+	}
 	return svc, nil
 }
 
@@ -48,45 +72,47 @@ func (s *ClassificationService) ensurePatterns(ctx context.Context, defaultPatte
 				continue
 			}
 
-			model := &domain.ClassificationPattern{
-				ID:              uuid.New(),
-				InformationType: domain.InformationType(seed.InformationType),
-				Pattern:         seed.Pattern,
-				Description:     seed.Description,
-				Priority:        seed.Priority,
-				IsActive:        true,
-				CreatedAt:       time.Now().UTC(),
-				UpdatedAt:       time.Now().UTC(),
-			}
-
-			if err := s.repo.Create(ctx, model); err != nil {
+			if err := s.repo.Create(ctx, seed.model("")); err != nil {
 				return fmt.Errorf("failed to seed pattern %s: %w", seed.Pattern, err)
 			}
 		}
-
-		patterns, err = s.repo.GetActive(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to reload patterns: %w", err)
-		}
 	}
 
-	return s.refreshClassifier(patterns)
+	return nil
 }
 
-func (s *ClassificationService) refreshClassifier(patterns []*domain.ClassificationPattern) error {
+func (s *ClassificationService) refreshClassifier(patterns []*domain.ClassificationPattern, abbreviations []*domain.Abbreviation) error {
 	matcher, err := classifier.NewClassifier(patterns)
 	if err != nil {
 		return fmt.Errorf("failed to prepare classifier: %w", err)
 	}
+	matcher.SetAbbreviations(abbreviations)
+
+	items := make([]any, 0, len(patterns)+len(abbreviations))
+	for _, p := range patterns {
+		items = append(items, p)
+	}
+	for _, a := range abbreviations {
+		items = append(items, a)
+	}
+	version, err := configHash(items)
+	if err != nil {
+		return err
+	}
 
 	s.mu.Lock()
 	s.matcher = matcher
+	s.matcherVersion = version
 	s.mu.Unlock()
 
 	return nil
 }
 
 func (s *ClassificationService) CreatePattern(ctx context.Context, req *domain.CreatePatternRequest) (uuid.UUID, error) {
+	if err := s.validatePatternRequest(ctx, req); err != nil {
+		return uuid.Nil, err
+	}
+
 	exists, err := s.repo.ExistsByPattern(ctx, req.Pattern)
 	if err != nil {
 		return uuid.Nil, err
@@ -98,14 +124,25 @@ func (s *ClassificationService) CreatePattern(ctx context.Context, req *domain.C
 	id := uuid.New()
 	now := time.Now().UTC()
 	pattern := &domain.ClassificationPattern{
-		ID:              id,
-		InformationType: req.InformationType,
-		Pattern:         req.Pattern,
-		Description:     req.Description,
-		Priority:        req.Priority,
-		IsActive:        true,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                  id,
+		InformationType:     req.InformationType,
+		Pattern:             req.Pattern,
+		Description:         req.Description,
+		Priority:            req.Priority,
+		Validator:           req.Validator,
+		Target:              req.Target,
+		MatchTokens:         req.MatchTokens,
+		Pack:                req.Pack,
+		TablePattern:        req.TablePattern,
+		CoColumns:           req.CoColumns,
+		ContextBoost:        req.ContextBoost,
+		AllowedDataTypes:    req.AllowedDataTypes,
+		DisallowedDataTypes: req.DisallowedDataTypes,
+		MinLength:           req.MinLength,
+		MaxLength:           req.MaxLength,
+		IsActive:            true,
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	}
 
 	if err := s.repo.Create(ctx, pattern); err != nil {
@@ -132,6 +169,10 @@ func (s *ClassificationService) GetAllPatterns(ctx context.Context) ([]*domain.C
 }
 
 func (s *ClassificationService) UpdatePattern(ctx context.Context, id uuid.UUID, req *domain.CreatePatternRequest) error {
+	if err := s.validatePatternRequest(ctx, req); err != nil {
+		return err
+	}
+
 	pattern, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -141,6 +182,17 @@ func (s *ClassificationService) UpdatePattern(ctx context.Context, id uuid.UUID,
 	pattern.Pattern = req.Pattern
 	pattern.Description = req.Description
 	pattern.Priority = req.Priority
+	pattern.Validator = req.Validator
+	pattern.Target = req.Target
+	pattern.MatchTokens = req.MatchTokens
+	pattern.Pack = req.Pack
+	pattern.TablePattern = req.TablePattern
+	pattern.CoColumns = req.CoColumns
+	pattern.ContextBoost = req.ContextBoost
+	pattern.AllowedDataTypes = req.AllowedDataTypes
+	pattern.DisallowedDataTypes = req.DisallowedDataTypes
+	pattern.MinLength = req.MinLength
+	pattern.MaxLength = req.MaxLength
 	pattern.UpdatedAt = time.Now().UTC()
 	pattern.IsActive = true
 
@@ -159,17 +211,139 @@ func (s *ClassificationService) DeletePattern(ctx context.Context, id uuid.UUID)
 	return s.reloadMatcher(ctx)
 }
 
-func (s *ClassificationService) ClassifyColumn(columnName string) (domain.InformationType, float64, []string) {
+func (s *ClassificationService) ClassifyColumn(column domain.ColumnContext) (domain.InformationType, float64, []string, []domain.ContextRuleMatch) {
 	s.mu.RLock()
 	matcher := s.matcher
 	s.mu.RUnlock()
 
 	if matcher == nil {
-		return domain.InfoTypeNA, 0.0, []string{}
+		return domain.InfoTypeNA, 0.0, []string{}, nil
+	}
+
+	res := matcher.ClassifyColumn(column)
+	return res.InformationType, res.ConfidenceScore, res.MatchedPatterns, res.ContextRules
+}
+
+func (s *ClassificationService) ClassifyValues(dataType string, values []string) (domain.InformationType, float64, int, string) {
+	s.mu.RLock()
+	matcher := s.matcher
+	s.mu.RUnlock()
+
+	if matcher == nil {
+		return domain.InfoTypeNA, 0.0, 0, ""
+	}
+
+	res := matcher.ClassifyValues(dataType, values)
+	return res.InformationType, res.ConfidenceScore, res.MatchedSamples, res.Detector
+}
+
+func (s *ClassificationService) ValidateValues(pattern string, values []string) (int, int, bool) {
+	s.mu.RLock()
+	matcher := s.matcher
+	s.mu.RUnlock()
+
+	if matcher == nil {
+		return 0, 0, false
+	}
+
+	return matcher.ValidateSamples(pattern, values)
+}
+
+// TestPatterns classifies column names with a candidate pattern, the active
+// patterns or both, without storing anything, and explains every match.
+func (s *ClassificationService) TestPatterns(ctx context.Context, req *domain.TestPatternsRequest) ([]*domain.ColumnExplanation, error) {
+	packs, err := connectionPacks(req.Packs)
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []*domain.ClassificationPattern
+	if req.Pattern == nil || req.IncludeActive {
+		patterns, err = s.repo.GetActive(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if candidate := req.Pattern; candidate != nil {
+		if err := s.validatePatternRequest(ctx, candidate); err != nil {
+			return nil, err
+		}
+		if candidate.Pack != "" && !containsString(packs, candidate.Pack) {
+			packs = append(packs, candidate.Pack)
+		}
+		patterns = append(patterns, &domain.ClassificationPattern{
+			InformationType:     candidate.InformationType,
+			Pattern:             candidate.Pattern,
+			Description:         candidate.Description,
+			Priority:            candidate.Priority,
+			Validator:           candidate.Validator,
+			Target:              candidate.Target,
+			MatchTokens:         candidate.MatchTokens,
+			Pack:                candidate.Pack,
+			TablePattern:        candidate.TablePattern,
+			CoColumns:           candidate.CoColumns,
+			ContextBoost:        candidate.ContextBoost,
+			AllowedDataTypes:    candidate.AllowedDataTypes,
+			DisallowedDataTypes: candidate.DisallowedDataTypes,
+			MinLength:           candidate.MinLength,
+			MaxLength:           candidate.MaxLength,
+			IsActive:            true,
+		})
+	}
+
+	matcher, err := classifier.NewClassifier(patterns)
+	if err != nil {
+		return nil, err
+	}
+	abbreviations, err := s.abbreviationRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matcher.SetAbbreviations(abbreviations)
+
+	explanations := make([]*domain.ColumnExplanation, len(req.Columns))
+	for i, name := range req.Columns {
+		explanation := matcher.ExplainColumn(domain.ColumnContext{
+			TableName:      req.TableName,
+			ColumnName:     name,
+			DataType:       req.DataType,
+			SiblingColumns: req.Columns,
+			Packs:          packs,
+		})
+		explanations[i] = &explanation
+	}
+
+	return explanations, nil
+}
+
+// validatePatternRequest checks the fields of a pattern that binding does not.
+func (s *ClassificationService) validatePatternRequest(ctx context.Context, req *domain.CreatePatternRequest) error {
+	if err := s.validateInformationType(ctx, req.InformationType); err != nil {
+		return err
+	}
+	if err := validateValidatorName(req.Validator); err != nil {
+		return err
+	}
+	if err := classifier.ValidateTarget(req.Target); err != nil {
+		return err
 	}
+	if err := classifier.ValidatePackName(req.Pack); err != nil {
+		return err
+	}
+	if err := classifier.ValidateContext(req.TablePattern, req.CoColumns); err != nil {
+		return err
+	}
+	return classifier.ValidateTypeConstraints(req.MinLength, req.MaxLength)
+}
 
-	res := matcher.ClassifyColumn(columnName)
-	return res.InformationType, res.ConfidenceScore, res.MatchedPatterns
+func validateValidatorName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := classifier.LookupValidator(name); !ok {
+		return fmt.Errorf("unknown validator %q, available validators: %s", name, strings.Join(classifier.ValidatorNames(), ", "))
+	}
+	return nil
 }
 
 func (s *ClassificationService) reloadMatcher(ctx context.Context) error {
@@ -177,14 +351,84 @@ func (s *ClassificationService) reloadMatcher(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	return s.refreshClassifier(patterns)
+	abbreviations, err := s.abbreviationRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	return s.refreshClassifier(patterns, abbreviations)
+}
+
+// ConfigVersion identifies the patterns, abbreviations and information type
+// catalog columns are currently classified with; it changes when any of them
+// is edited.
+func (s *ClassificationService) ConfigVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.matcherVersion + s.infoTypesVersion
+}
+
+// configHash hashes the JSON encoding of items regardless of their order.
+func configHash(items []any) (string, error) {
+	encoded := make([]string, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash classifier configuration: %w", err)
+		}
+		encoded = append(encoded, string(data))
+	}
+	sort.Strings(encoded)
+
+	h := sha256.New()
+	for _, e := range encoded {
+		h.Write([]byte(e))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8]), nil
 }
 
 type patternSeed struct {
-	InformationType string `json:"information_type"`
-	Pattern         string `json:"pattern"`
-	Description     string `json:"description"`
-	Priority        int    `json:"priority"`
+	InformationType     string   `json:"information_type"`
+	Pattern             string   `json:"pattern"`
+	Description         string   `json:"description"`
+	Priority            int      `json:"priority"`
+	Validator           string   `json:"validator"`
+	Target              string   `json:"target"`
+	MatchTokens         bool     `json:"match_tokens"`
+	TablePattern        string   `json:"table_pattern"`
+	CoColumns           []string `json:"co_columns"`
+	ContextBoost        float64  `json:"context_boost"`
+	AllowedDataTypes    []string `json:"allowed_data_types"`
+	DisallowedDataTypes []string `json:"disallowed_data_types"`
+	MinLength           *int     `json:"min_length"`
+	MaxLength           *int     `json:"max_length"`
+}
+
+// model returns the pattern a seed describes, installed by pack or, when pack
+// is empty, as a core pattern.
+func (seed patternSeed) model(pack string) *domain.ClassificationPattern {
+	now := time.Now().UTC()
+	return &domain.ClassificationPattern{
+		ID:                  uuid.New(),
+		InformationType:     domain.InformationType(seed.InformationType),
+		Pattern:             seed.Pattern,
+		Description:         seed.Description,
+		Priority:            seed.Priority,
+		Validator:           seed.Validator,
+		Target:              domain.PatternTarget(seed.Target),
+		MatchTokens:         seed.MatchTokens,
+		Pack:                pack,
+		TablePattern:        seed.TablePattern,
+		CoColumns:           seed.CoColumns,
+		ContextBoost:        seed.ContextBoost,
+		AllowedDataTypes:    seed.AllowedDataTypes,
+		DisallowedDataTypes: seed.DisallowedDataTypes,
+		MinLength:           seed.MinLength,
+		MaxLength:           seed.MaxLength,
+		IsActive:            true,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
 }
 
 func loadPatternSeeds(path string) ([]patternSeed, error) {
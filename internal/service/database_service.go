@@ -3,40 +3,78 @@ package service
 import (
     "context"
     "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
     "time"
 
     "github.com/google/uuid"
 
     "database-classifier/internal/domain"
     "database-classifier/internal/infrastructure/database"
+    "database-classifier/pkg/classifier"
     "database-classifier/pkg/security"
 )
 
 type DatabaseService struct {
-    dbConnRepo domain.DatabaseConnectionRepository
-    encryptor  *security.Encryptor
-    inspector  *database.MySQLInspector
+    dbConnRepo     domain.DatabaseConnectionRepository
+    riskPolicyRepo domain.RiskPolicyRepository
+    encryptor      *security.Encryptor
+    sqlite         SQLiteOptions
+}
+
+type SQLiteOptions struct {
+	// UploadDir is where uploaded SQLite files are stored. Files in it may
+	// always be used by SQLite connections.
+	UploadDir string
+	// AllowedDirs lists the server-local directories whose files SQLite
+	// connections may name. Without any, only uploaded files can be scanned.
+	AllowedDirs []string
 }
 
 func NewDatabaseService(
 	dbConnRepo domain.DatabaseConnectionRepository,
+	riskPolicyRepo domain.RiskPolicyRepository,
 	encryptor *security.Encryptor,
+	sqlite SQLiteOptions,
 ) *DatabaseService {
 	return &DatabaseService{
-		dbConnRepo: dbConnRepo,
-		encryptor:  encryptor,
-		inspector:  database.NewMySQLInspector(),
+		dbConnRepo:     dbConnRepo,
+		riskPolicyRepo: riskPolicyRepo,
+		encryptor:      encryptor,
+		sqlite:         sqlite,
 	}
 }
 
 func (s *DatabaseService) CreateConnection(ctx context.Context, req *domain.CreateDatabaseRequest) (uuid.UUID, error) {
-    err := s.inspector.TestConnection(req.Host, req.Port, req.Username, req.Password, req.DatabaseName)
+    engine := engineOrDefault(req.Engine)
+    filePath, err := s.resolveFilePath(engine, req.FilePath)
+    if err != nil {
+        return uuid.Nil, err
+    }
+
+    scanScope, err := connectionScope(req.ScanScope)
+    if err != nil {
+        return uuid.Nil, err
+    }
+
+    patternPacks, err := connectionPacks(req.PatternPacks)
     if err != nil {
-        return uuid.Nil, fmt.Errorf("failed to connect to MySQL database: %w", err)
+        return uuid.Nil, err
+    }
+
+    if err := s.validateRiskPolicy(ctx, req.RiskPolicyID); err != nil {
+        return uuid.Nil, err
+    }
+
+    err = testConnection(ctx, engine, req.Host, req.Port, req.Username, req.Password, inspectedDatabase(engine, req.DatabaseName, filePath))
+    if err != nil {
+        return uuid.Nil, fmt.Errorf("failed to connect to %s database: %w", engine, err)
     }
 
     // Encrypt the password
-    encryptedPassword, err := s.encryptor.Encrypt(req.Password)
+    encryptedPassword, err := s.encryptPassword(engine, req.Password)
     if err != nil {
         return uuid.Nil, fmt.Errorf("failed to encrypt password: %w", err)
     }
@@ -45,12 +83,19 @@ func (s *DatabaseService) CreateConnection(ctx context.Context, req *domain.Crea
     now := time.Now().UTC()
     conn := &domain.DatabaseConnection{
         ID:                id,
+        Engine:            engine,
         Host:              req.Host,
         Port:              req.Port,
         Username:          req.Username,
         EncryptedPassword: encryptedPassword,
         DatabaseName:      req.DatabaseName,
+        FilePath:          filePath,
         Description:       req.Description,
+        SampleSize:        req.SampleSize,
+        ScanScope:         scanScope,
+        ScanConcurrency:   req.ScanConcurrency,
+        PatternPacks:      patternPacks,
+        RiskPolicyID:      req.RiskPolicyID,
         IsActive:          true,
         CreatedAt:         now,
         UpdatedAt:         now,
@@ -87,35 +132,66 @@ func (s *DatabaseService) UpdateConnection(ctx context.Context, id uuid.UUID, re
 		return fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	needsTest := conn.Host != req.Host ||
+	engine := engineOrDefault(req.Engine)
+	filePath, err := s.resolveFilePath(engine, req.FilePath)
+	if err != nil {
+		return err
+	}
+
+	scanScope, err := connectionScope(req.ScanScope)
+	if err != nil {
+		return err
+	}
+
+	patternPacks, err := connectionPacks(req.PatternPacks)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateRiskPolicy(ctx, req.RiskPolicyID); err != nil {
+		return err
+	}
+
+	needsTest := conn.Engine != engine ||
+		conn.Host != req.Host ||
 		conn.Port != req.Port ||
 		conn.Username != req.Username ||
-		conn.DatabaseName != req.DatabaseName
+		conn.DatabaseName != req.DatabaseName ||
+		conn.FilePath != filePath
 
 	if req.Password != "" || needsTest {
 		password := req.Password
 		if password == "" {
-			password, err = s.encryptor.Decrypt(conn.EncryptedPassword)
+			password, err = s.decryptPassword(conn)
 			if err != nil {
 				return fmt.Errorf("failed to decrypt existing password: %w", err)
 			}
 		}
 
-		err = s.inspector.TestConnection(req.Host, req.Port, req.Username, password, req.DatabaseName)
+		err = testConnection(ctx, engine, req.Host, req.Port, req.Username, password, inspectedDatabase(engine, req.DatabaseName, filePath))
 		if err != nil {
-			return fmt.Errorf("failed to connect to MySQL database: %w", err)
+			return fmt.Errorf("failed to connect to %s database: %w", engine, err)
 		}
 	}
 
+    conn.Engine = engine
     conn.Host = req.Host
     conn.Port = req.Port
     conn.Username = req.Username
     conn.DatabaseName = req.DatabaseName
+    conn.FilePath = filePath
     conn.Description = req.Description
+    conn.SampleSize = req.SampleSize
+    conn.ScanScope = scanScope
+    conn.ScanConcurrency = req.ScanConcurrency
+    conn.PatternPacks = patternPacks
+    conn.RiskPolicyID = req.RiskPolicyID
     conn.UpdatedAt = time.Now().UTC()
 
-    if req.Password != "" {
-		encryptedPassword, err := s.encryptor.Encrypt(req.Password)
+    if engine == domain.EngineSQLite {
+		conn.EncryptedPassword = ""
+	} else if req.Password != "" {
+		encryptedPassword, err := s.encryptPassword(engine, req.Password)
 		if err != nil {
 			return fmt.Errorf("failed to encrypt password: %w", err)
 		}
@@ -130,10 +206,22 @@ func (s *DatabaseService) UpdateConnection(ctx context.Context, id uuid.UUID, re
 }
 
 func (s *DatabaseService) DeleteConnection(ctx context.Context, id uuid.UUID) error {
+    conn, err := s.dbConnRepo.GetByID(ctx, id)
+    if err != nil {
+        return fmt.Errorf("failed to get database connection: %w", err)
+    }
+
     if err := s.dbConnRepo.Delete(ctx, id); err != nil {
         return fmt.Errorf("failed to delete database connection: %w", err)
     }
 
+    // Uploaded files belong to their connection; server-local files do not
+    if conn.Engine == domain.EngineSQLite && s.isUploadedFile(conn.FilePath) {
+        if err := os.Remove(conn.FilePath); err != nil && !os.IsNotExist(err) {
+            fmt.Printf("Warning: failed to remove uploaded SQLite file %s: %v\n", conn.FilePath, err)
+        }
+    }
+
     return nil
 }
 
@@ -143,15 +231,240 @@ func (s *DatabaseService) TestConnection(ctx context.Context, id uuid.UUID) erro
 		return fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	password, err := s.encryptor.Decrypt(conn.EncryptedPassword)
+	password, err := s.decryptPassword(conn)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt password: %w", err)
 	}
 
-	err = s.inspector.TestConnection(conn.Host, conn.Port, conn.Username, password, conn.DatabaseName)
+	err = testConnection(ctx, conn.Engine, conn.Host, conn.Port, conn.Username, password, connectionDatabase(conn))
 	if err != nil {
 		return fmt.Errorf("connection test failed: %w", err)
 	}
 
     return nil
 }
+
+// ImportSQLiteFile stores an uploaded SQLite database in the upload directory
+// and registers a connection for it. The file is only kept if it can be read
+// as a SQLite database.
+func (s *DatabaseService) ImportSQLiteFile(ctx context.Context, file io.Reader, description string, sampleSize int) (uuid.UUID, error) {
+	if s.sqlite.UploadDir == "" {
+		return uuid.Nil, fmt.Errorf("SQLite uploads are not configured")
+	}
+	if err := os.MkdirAll(s.sqlite.UploadDir, 0o750); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create SQLite upload directory: %w", err)
+	}
+
+	id := uuid.New()
+	uploadDir, err := filepath.Abs(s.sqlite.UploadDir)
+	if err == nil {
+		uploadDir, err = filepath.EvalSymlinks(uploadDir)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to resolve SQLite upload directory: %w", err)
+	}
+	path := filepath.Join(uploadDir, id.String()+".sqlite")
+
+	// Write under a temporary name so that a partial upload is never
+	// mistaken for a stored file
+	tmp, err := os.CreateTemp(uploadDir, ".upload-*")
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to store SQLite file: %w", err)
+	}
+	_, err = io.Copy(tmp, file)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return uuid.Nil, fmt.Errorf("failed to store SQLite file: %w", err)
+	}
+
+	if err := testConnection(ctx, domain.EngineSQLite, "", 0, "", "", path); err != nil {
+		os.Remove(path)
+		return uuid.Nil, fmt.Errorf("%w: %v", domain.ErrInvalidSQLiteFile, err)
+	}
+
+	now := time.Now().UTC()
+	conn := &domain.DatabaseConnection{
+		ID:          id,
+		Engine:      domain.EngineSQLite,
+		FilePath:    path,
+		Description: description,
+		SampleSize:  sampleSize,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.dbConnRepo.Create(ctx, conn); err != nil {
+		os.Remove(path)
+		return uuid.Nil, fmt.Errorf("failed to save database connection: %w", err)
+	}
+
+	return id, nil
+}
+
+// resolveFilePath returns the absolute path, with symlinks resolved, of the
+// file a SQLite connection names, provided it is an uploaded file or lies in
+// one of the allowed directories. Other engines have no file.
+func (s *DatabaseService) resolveFilePath(engine domain.Engine, path string) (string, error) {
+	if engine != domain.EngineSQLite {
+		return "", nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid SQLite file path: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open SQLite file: %w", err)
+	}
+
+	for _, dir := range append([]string{s.sqlite.UploadDir}, s.sqlite.AllowedDirs...) {
+		if isWithinDir(dir, resolved) {
+			return resolved, nil
+		}
+	}
+
+	return "", fmt.Errorf("SQLite file %s is outside the allowed directories", path)
+}
+
+func (s *DatabaseService) isUploadedFile(path string) bool {
+	return path != "" && isWithinDir(s.sqlite.UploadDir, path)
+}
+
+// SQLite connections have no password, and the encryptor refuses to encrypt
+// an empty one.
+func (s *DatabaseService) encryptPassword(engine domain.Engine, password string) (string, error) {
+	if engine == domain.EngineSQLite {
+		return "", nil
+	}
+	return s.encryptor.Encrypt(password)
+}
+
+func (s *DatabaseService) decryptPassword(conn *domain.DatabaseConnection) (string, error) {
+	return decryptPassword(s.encryptor, conn)
+}
+
+// engineOrDefault keeps requests written before PostgreSQL support working:
+// a connection without an engine is MySQL.
+func engineOrDefault(engine domain.Engine) domain.Engine {
+	if engine == "" {
+		return domain.EngineMySQL
+	}
+	return engine
+}
+
+// connectionScope validates the scan scope of a connection request. Database
+// is derived from database_name when a scan starts, so it is not stored.
+func connectionScope(scope *domain.ScanScope) (*domain.ScanScope, error) {
+	if scope == nil {
+		return nil, nil
+	}
+
+	stored := *scope
+	stored.Database = ""
+	if scopeIsEmpty(&stored) {
+		return nil, nil
+	}
+	if _, err := compileScope(&stored); err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// connectionPacks validates the pattern packs enabled for a connection and
+// returns them lower-cased without duplicates. Packs need not be installed
+// yet; until they are they contribute no patterns.
+func connectionPacks(packs []string) ([]string, error) {
+	var stored []string
+	for _, pack := range packs {
+		pack = strings.ToLower(strings.TrimSpace(pack))
+		if err := classifier.ValidatePackName(pack); err != nil {
+			return nil, err
+		}
+		if pack == "" || containsString(stored, pack) {
+			continue
+		}
+		stored = append(stored, pack)
+	}
+	return stored, nil
+}
+
+// validateRiskPolicy checks that the risk policy assigned to a connection, if
+// any, exists.
+func (s *DatabaseService) validateRiskPolicy(ctx context.Context, id *uuid.UUID) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := s.riskPolicyRepo.GetByID(ctx, *id); err != nil {
+		return fmt.Errorf("unknown risk policy %s", id)
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// decryptPassword returns the plaintext password of conn; SQLite connections
+// have none.
+func decryptPassword(encryptor *security.Encryptor, conn *domain.DatabaseConnection) (string, error) {
+	if conn.Engine == domain.EngineSQLite {
+		return "", nil
+	}
+	return encryptor.Decrypt(conn.EncryptedPassword)
+}
+
+// connectionDatabase is the database argument an inspector expects for conn:
+// the file for SQLite, the database name otherwise.
+func connectionDatabase(conn *domain.DatabaseConnection) string {
+	return inspectedDatabase(conn.Engine, conn.DatabaseName, conn.FilePath)
+}
+
+func inspectedDatabase(engine domain.Engine, databaseName, filePath string) string {
+	if engine == domain.EngineSQLite {
+		return filePath
+	}
+	return databaseName
+}
+
+// isWithinDir reports whether path, already absolute and free of symlinks,
+// lies inside dir.
+func isWithinDir(dir, path string) bool {
+	if dir == "" {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+
+	rel, err := filepath.Rel(absDir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func testConnection(ctx context.Context, engine domain.Engine, host string, port int, username, password, databaseName string) error {
+	inspector, err := database.NewInspector(engine)
+	if err != nil {
+		return err
+	}
+	return inspector.TestConnection(ctx, host, port, username, password, databaseName)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"database-classifier/internal/domain"
+)
+
+func TestIsWithinDir(t *testing.T) {
+	dir := t.TempDir()
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("failed to resolve %s: %v", dir, err)
+	}
+
+	tests := []struct {
+		dir  string
+		path string
+		want bool
+	}{
+		{dir, filepath.Join(resolvedDir, "a.db"), true},
+		{dir, filepath.Join(resolvedDir, "nested", "a.db"), true},
+		{dir, resolvedDir, false},
+		{dir, filepath.Dir(resolvedDir), false},
+		{dir, resolvedDir + "-other" + string(filepath.Separator) + "a.db", false},
+		{dir, filepath.Join(resolvedDir, "..a.db"), true},
+		{"", filepath.Join(resolvedDir, "a.db"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isWithinDir(tt.dir, tt.path); got != tt.want {
+			t.Errorf("isWithinDir(%q, %q) = %t, want %t", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveFilePath(t *testing.T) {
+	root := t.TempDir()
+	uploadDir := filepath.Join(root, "uploads")
+	allowedDir := filepath.Join(root, "allowed")
+	outsideDir := filepath.Join(root, "outside")
+	for _, dir := range []string{uploadDir, allowedDir, outsideDir} {
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	files := map[string]string{
+		"uploaded": filepath.Join(uploadDir, "uploaded.db"),
+		"allowed":  filepath.Join(allowedDir, "allowed.db"),
+		"outside":  filepath.Join(outsideDir, "outside.db"),
+	}
+	for _, path := range files {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+	}
+	escape := filepath.Join(allowedDir, "escape.db")
+	if err := os.Symlink(files["outside"], escape); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	inside := filepath.Join(outsideDir, "inside.db")
+	if err := os.Symlink(files["allowed"], inside); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	svc := &DatabaseService{sqlite: SQLiteOptions{UploadDir: uploadDir, AllowedDirs: []string{allowedDir}}}
+	resolve := func(path string) string {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			t.Fatalf("failed to resolve %s: %v", path, err)
+		}
+		return resolved
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+		ok   bool
+	}{
+		{"uploaded file", files["uploaded"], resolve(files["uploaded"]), true},
+		{"allowed file", files["allowed"], resolve(files["allowed"]), true},
+		{"file outside", files["outside"], "", false},
+		{"dot-dot out of allowed dir", filepath.Join(allowedDir, "..", "outside", "outside.db"), "", false},
+		{"symlink out of allowed dir", escape, "", false},
+		{"symlink into allowed dir", inside, resolve(files["allowed"]), true},
+		{"missing file", filepath.Join(allowedDir, "missing.db"), "", false},
+		{"allowed dir itself", allowedDir, "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := svc.resolveFilePath(domain.EngineSQLite, tt.path)
+		if tt.ok && (err != nil || got != tt.want) {
+			t.Errorf("%s: resolveFilePath(%q) = %q, %v, want %q", tt.name, tt.path, got, err, tt.want)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: resolveFilePath(%q) = %q, want an error", tt.name, tt.path, got)
+		}
+	}
+
+	if got, err := svc.resolveFilePath(domain.EngineMySQL, files["outside"]); got != "" || err != nil {
+		t.Errorf("resolveFilePath for MySQL = %q, %v, want no file", got, err)
+	}
+}
+
+func TestResolveFilePathWithoutAllowedDirs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "local.db")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+
+	svc := &DatabaseService{sqlite: SQLiteOptions{UploadDir: filepath.Join(dir, "uploads")}}
+	if got, err := svc.resolveFilePath(domain.EngineSQLite, path); err == nil {
+		t.Errorf("resolveFilePath(%q) = %q, want only uploaded files to be accepted", path, got)
+	}
+}
+
+func TestImportSQLiteFileRejectsOtherFiles(t *testing.T) {
+	uploadDir := t.TempDir()
+	svc := &DatabaseService{sqlite: SQLiteOptions{UploadDir: uploadDir}}
+
+	_, err := svc.ImportSQLiteFile(context.Background(), strings.NewReader("not a database, just some text that is long enough"), "", 0)
+	if !errors.Is(err, domain.ErrInvalidSQLiteFile) {
+		t.Fatalf("ImportSQLiteFile = %v, want %v", err, domain.ErrInvalidSQLiteFile)
+	}
+
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		t.Fatalf("failed to read upload directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("upload directory holds %d files, want the rejected file removed", len(entries))
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"database-classifier/internal/domain"
+)
+
+const (
+	defaultFindingsLimit = 100
+	maxFindingsLimit     = 1000
+)
+
+type FindingService struct {
+	findingRepo domain.FindingRepository
+}
+
+func NewFindingService(findingRepo domain.FindingRepository) *FindingService {
+	return &FindingService{findingRepo: findingRepo}
+}
+
+// SearchFindings normalizes filter in place, applying the default and maximum
+// page size, and runs the search.
+func (s *FindingService) SearchFindings(ctx context.Context, filter *domain.FindingFilter) ([]*domain.Finding, int, error) {
+	if filter.MinConfidence < 0 || filter.MinConfidence > 1 {
+		return nil, 0, fmt.Errorf("%w: min_confidence must be between 0 and 1", domain.ErrInvalidFindingFilter)
+	}
+
+	for i, infoType := range filter.InformationTypes {
+		filter.InformationTypes[i] = domain.InformationType(strings.ToUpper(strings.TrimSpace(string(infoType))))
+	}
+
+	for i, level := range filter.RiskLevels {
+		level = domain.RiskLevel(strings.ToLower(strings.TrimSpace(string(level))))
+		switch level {
+		case domain.RiskLevelLow, domain.RiskLevelMedium, domain.RiskLevelHigh, domain.RiskLevelCritical:
+			filter.RiskLevels[i] = level
+		default:
+			return nil, 0, fmt.Errorf("%w: unknown risk level: %s", domain.ErrInvalidFindingFilter, level)
+		}
+	}
+
+	for i, dataType := range filter.DataTypes {
+		filter.DataTypes[i] = strings.ToLower(strings.TrimSpace(dataType))
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultFindingsLimit
+	}
+	if filter.Limit > maxFindingsLimit {
+		filter.Limit = maxFindingsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	findings, total, err := s.findingRepo.Search(ctx, *filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search findings: %w", err)
+	}
+
+	if findings == nil {
+		findings = []*domain.Finding{}
+	}
+
+	return findings, total, nil
+}
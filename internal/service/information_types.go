@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"database-classifier/internal/domain"
+)
+
+var informationTypeNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+// ensureInformationTypes seeds the information type catalog from
+// defaultInformationTypesPath while it is empty, then registers as low
+// sensitivity any type stored patterns use that the catalog lacks, so that
+// patterns created before the catalog existed stay valid.
+func (s *ClassificationService) ensureInformationTypes(ctx context.Context, defaultInformationTypesPath string) error {
+	infoTypes, err := s.infoTypeRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load information types: %w", err)
+	}
+
+	known := make(map[domain.InformationType]bool, len(infoTypes))
+	for _, infoType := range infoTypes {
+		known[infoType.Name] = true
+	}
+
+	if len(infoTypes) == 0 && defaultInformationTypesPath != "" {
+		seeds, err := loadInformationTypeSeeds(defaultInformationTypesPath)
+		if err != nil {
+			return err
+		}
+		for _, seed := range seeds {
+			model, err := newInformationType(&seed)
+			if err != nil {
+				return fmt.Errorf("invalid seed information type %s: %w", seed.Name, err)
+			}
+			if err := s.infoTypeRepo.Create(ctx, model); err != nil {
+				return fmt.Errorf("failed to seed information type %s: %w", seed.Name, err)
+			}
+			known[model.Name] = true
+		}
+	}
+
+	patterns, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load patterns: %w", err)
+	}
+	for _, pattern := range patterns {
+		if known[pattern.InformationType] {
+			continue
+		}
+
+		fmt.Printf("Warning: registering information type %s used by existing patterns with low sensitivity\n", pattern.InformationType)
+		now := time.Now().UTC()
+		model := &domain.InformationTypeDefinition{
+			Name:        pattern.InformationType,
+			Description: "Registered from existing patterns",
+			Sensitivity: domain.SensitivityLow,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.infoTypeRepo.Create(ctx, model); err != nil {
+			return fmt.Errorf("failed to register information type %s: %w", pattern.InformationType, err)
+		}
+		known[pattern.InformationType] = true
+	}
+
+	return nil
+}
+
+func (s *ClassificationService) CreateInformationType(ctx context.Context, req *domain.CreateInformationTypeRequest) error {
+	model, err := newInformationType(req)
+	if err != nil {
+		return err
+	}
+
+	catalog, err := s.informationTypeCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	if _, exists := catalog[model.Name]; exists {
+		return fmt.Errorf("information type already exists")
+	}
+	if err := validateParent(catalog, model.Name, model.Parent); err != nil {
+		return err
+	}
+
+	if err := s.infoTypeRepo.Create(ctx, model); err != nil {
+		return fmt.Errorf("failed to create information type: %w", err)
+	}
+
+	return s.reloadInformationTypes(ctx)
+}
+
+func (s *ClassificationService) GetInformationType(ctx context.Context, name domain.InformationType) (*domain.InformationTypeDefinition, error) {
+	return s.infoTypeRepo.GetByName(ctx, name)
+}
+
+func (s *ClassificationService) GetAllInformationTypes(ctx context.Context) ([]*domain.InformationTypeDefinition, error) {
+	return s.infoTypeRepo.GetAll(ctx)
+}
+
+// UpdateInformationType replaces the description, sensitivity, parent and
+// tags of a type. Types cannot be renamed, since patterns and scan results
+// refer to them by name.
+func (s *ClassificationService) UpdateInformationType(ctx context.Context, name domain.InformationType, req *domain.CreateInformationTypeRequest) error {
+	if req.Name != name {
+		return fmt.Errorf("information type %s cannot be renamed", name)
+	}
+	update, err := newInformationType(req)
+	if err != nil {
+		return err
+	}
+
+	catalog, err := s.informationTypeCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	model, ok := catalog[name]
+	if !ok {
+		return fmt.Errorf("information type not found")
+	}
+	if err := validateParent(catalog, name, update.Parent); err != nil {
+		return err
+	}
+
+	model.Description = update.Description
+	model.Sensitivity = update.Sensitivity
+	model.Parent = update.Parent
+	model.RegulatoryTags = update.RegulatoryTags
+	model.UpdatedAt = time.Now().UTC()
+
+	if err := s.infoTypeRepo.Update(ctx, model); err != nil {
+		return fmt.Errorf("failed to update information type: %w", err)
+	}
+
+	return s.reloadInformationTypes(ctx)
+}
+
+// DeleteInformationType removes a type no pattern, child type or value
+// detector refers to.
+func (s *ClassificationService) DeleteInformationType(ctx context.Context, name domain.InformationType) error {
+	if s.detectedByValues(name) {
+		return fmt.Errorf("information type %s is reported by the value detectors and cannot be deleted", name)
+	}
+
+	patterns, err := s.repo.GetByInformationType(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(patterns) > 0 {
+		return fmt.Errorf("information type %s is used by %d patterns", name, len(patterns))
+	}
+
+	catalog, err := s.informationTypeCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	for _, infoType := range catalog {
+		if infoType.Parent == name {
+			return fmt.Errorf("information type %s is the parent of %s", name, infoType.Name)
+		}
+	}
+
+	if err := s.infoTypeRepo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	return s.reloadInformationTypes(ctx)
+}
+
+// detectedByValues reports whether a value detector attributes columns to
+// name, which sampling does regardless of the patterns.
+func (s *ClassificationService) detectedByValues(name domain.InformationType) bool {
+	s.mu.RLock()
+	matcher := s.matcher
+	s.mu.RUnlock()
+
+	for _, detector := range matcher.GetValueDetectors() {
+		if detector.InformationType == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Sensitivity returns the tier of an information type in the catalog, or an
+// empty tier for types the catalog does not define.
+func (s *ClassificationService) Sensitivity(infoType domain.InformationType) domain.SensitivityTier {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if definition, ok := s.infoTypes[infoType]; ok {
+		return definition.Sensitivity
+	}
+	return ""
+}
+
+// validateInformationType checks that patterns classify into a type defined in
+// the catalog.
+func (s *ClassificationService) validateInformationType(ctx context.Context, infoType domain.InformationType) error {
+	if _, err := s.infoTypeRepo.GetByName(ctx, infoType); err != nil {
+		return fmt.Errorf("unknown information type %q, define it in /api/v1/information-types first", infoType)
+	}
+	return nil
+}
+
+func (s *ClassificationService) reloadInformationTypes(ctx context.Context) error {
+	catalog, err := s.informationTypeCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := make([]any, 0, len(catalog))
+	for _, infoType := range catalog {
+		items = append(items, infoType)
+	}
+	version, err := configHash(items)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.infoTypes = catalog
+	s.infoTypesVersion = version
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *ClassificationService) informationTypeCatalog(ctx context.Context) (map[domain.InformationType]*domain.InformationTypeDefinition, error) {
+	infoTypes, err := s.infoTypeRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make(map[domain.InformationType]*domain.InformationTypeDefinition, len(infoTypes))
+	for _, infoType := range infoTypes {
+		catalog[infoType.Name] = infoType
+	}
+	return catalog, nil
+}
+
+// validateParent checks that parent is empty or an existing type that does not
+// descend from name.
+func validateParent(catalog map[domain.InformationType]*domain.InformationTypeDefinition, name, parent domain.InformationType) error {
+	for ancestor := parent; ancestor != ""; {
+		if ancestor == name {
+			return fmt.Errorf("information type %s cannot be its own ancestor", name)
+		}
+		definition, ok := catalog[ancestor]
+		if !ok {
+			return fmt.Errorf("unknown parent information type %q", ancestor)
+		}
+		ancestor = definition.Parent
+	}
+	return nil
+}
+
+// newInformationType validates a catalog entry and returns it with its
+// regulatory tags upper-cased and without duplicates.
+func newInformationType(req *domain.CreateInformationTypeRequest) (*domain.InformationTypeDefinition, error) {
+	if !informationTypeNameRegex.MatchString(string(req.Name)) {
+		return nil, fmt.Errorf("information type name %q must be upper-case letters, digits and underscores", req.Name)
+	}
+	switch req.Sensitivity {
+	case domain.SensitivityHigh, domain.SensitivityMedium, domain.SensitivityLow:
+	default:
+		return nil, fmt.Errorf("unknown sensitivity tier '%s'", req.Sensitivity)
+	}
+
+	var tags []string
+	for _, tag := range req.RegulatoryTags {
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+		if tag == "" || containsString(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	now := time.Now().UTC()
+	return &domain.InformationTypeDefinition{
+		Name:           req.Name,
+		Description:    req.Description,
+		Sensitivity:    req.Sensitivity,
+		Parent:         req.Parent,
+		RegulatoryTags: tags,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+func loadInformationTypeSeeds(path string) ([]domain.CreateInformationTypeRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read information types file: %w", err)
+	}
+
+	var seeds []domain.CreateInformationTypeRequest
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return nil, fmt.Errorf("failed to parse information types file: %w", err)
+	}
+
+	return seeds, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"database-classifier/internal/domain"
+	"database-classifier/pkg/classifier"
+)
+
+// patternPackFile is the format of the files in the packs directory, one per
+// pack, named after it, e.g. configs/packs/pt-br.json.
+type patternPackFile struct {
+	Description string        `json:"description"`
+	Patterns    []patternSeed `json:"patterns"`
+}
+
+// ListPatternPacks returns the packs available in the packs directory with the
+// number of their patterns that are installed.
+func (s *ClassificationService) ListPatternPacks(ctx context.Context) ([]*domain.PatternPack, error) {
+	installed, err := s.repo.CountByPack(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(s.packsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pattern packs: %w", err)
+	}
+	sort.Strings(paths)
+
+	packs := make([]*domain.PatternPack, 0, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if classifier.ValidatePackName(name) != nil {
+			continue
+		}
+
+		file, err := loadPatternPack(path)
+		if err != nil {
+			fmt.Printf("Warning: skipping pattern pack %s: %v\n", name, err)
+			continue
+		}
+
+		packs = append(packs, &domain.PatternPack{
+			Name:        name,
+			Description: file.Description,
+			Patterns:    len(file.Patterns),
+			Installed:   installed[name],
+		})
+	}
+
+	return packs, nil
+}
+
+// InstallPatternPack adds the patterns of a pack to the pattern table and
+// returns how many were added. Patterns already present, whether core or
+// installed before, are left as they are, so installing a pack again only
+// adds the patterns missing from it.
+func (s *ClassificationService) InstallPatternPack(ctx context.Context, name string) (int, error) {
+	if err := classifier.ValidatePackName(name); err != nil || name == "" {
+		return 0, domain.ErrPatternPackNotFound
+	}
+
+	file, err := loadPatternPack(filepath.Join(s.packsDir, name+".json"))
+	if err != nil {
+		return 0, err
+	}
+
+	models := make([]*domain.ClassificationPattern, len(file.Patterns))
+	for i, seed := range file.Patterns {
+		if err := s.validateInformationType(ctx, domain.InformationType(seed.InformationType)); err != nil {
+			return 0, fmt.Errorf("invalid pattern %s in pack %s: %w", seed.Pattern, name, err)
+		}
+		if err := validateValidatorName(seed.Validator); err != nil {
+			return 0, fmt.Errorf("invalid pattern %s in pack %s: %w", seed.Pattern, name, err)
+		}
+		models[i] = seed.model(name)
+	}
+	// Compile the whole pack before storing any of it
+	if _, err := classifier.NewClassifier(models); err != nil {
+		return 0, fmt.Errorf("invalid pattern pack %s: %w", name, err)
+	}
+
+	added := 0
+	for _, model := range models {
+		exists, err := s.repo.ExistsByPattern(ctx, model.Pattern)
+		if err != nil {
+			return added, err
+		}
+		if exists {
+			continue
+		}
+
+		if err := s.repo.Create(ctx, model); err != nil {
+			return added, fmt.Errorf("failed to install pattern %s: %w", model.Pattern, err)
+		}
+		added++
+	}
+
+	if err := s.reloadMatcher(ctx); err != nil {
+		return added, err
+	}
+
+	return added, nil
+}
+
+// UninstallPatternPack deletes the patterns installed by a pack and returns
+// how many there were. Connections keep the pack enabled, so installing it
+// again brings its patterns back for them.
+func (s *ClassificationService) UninstallPatternPack(ctx context.Context, name string) (int, error) {
+	if err := classifier.ValidatePackName(name); err != nil || name == "" {
+		return 0, domain.ErrPatternPackNotFound
+	}
+
+	removed, err := s.repo.DeleteByPack(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.reloadMatcher(ctx); err != nil {
+		return removed, err
+	}
+
+	return removed, nil
+}
+
+func loadPatternPack(path string) (*patternPackFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, domain.ErrPatternPackNotFound
+		}
+		return nil, fmt.Errorf("failed to read pattern pack: %w", err)
+	}
+
+	var file patternPackFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse pattern pack: %w", err)
+	}
+
+	return &file, nil
+}
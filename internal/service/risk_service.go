@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+// defaultRecomputeLimit is how many of the latest scans RecomputeRisk looks
+// at when the request does not say.
+const defaultRecomputeLimit = 10
+
+type RiskService struct {
+	policyRepo        domain.RiskPolicyRepository
+	dbConnRepo        domain.DatabaseConnectionRepository
+	scanRepo          domain.ScanResultRepository
+	classificationSvc domain.ClassificationService
+}
+
+func NewRiskService(
+	policyRepo domain.RiskPolicyRepository,
+	dbConnRepo domain.DatabaseConnectionRepository,
+	scanRepo domain.ScanResultRepository,
+	classificationSvc domain.ClassificationService,
+) *RiskService {
+	return &RiskService{
+		policyRepo:        policyRepo,
+		dbConnRepo:        dbConnRepo,
+		scanRepo:          scanRepo,
+		classificationSvc: classificationSvc,
+	}
+}
+
+func (s *RiskService) CreatePolicy(ctx context.Context, req *domain.CreateRiskPolicyRequest) (uuid.UUID, error) {
+	policy, err := s.newRiskPolicy(ctx, req)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.checkPolicyName(ctx, uuid.Nil, policy.Name); err != nil {
+		return uuid.Nil, err
+	}
+
+	policy.ID = uuid.New()
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create risk policy: %w", err)
+	}
+
+	return policy.ID, nil
+}
+
+func (s *RiskService) GetPolicy(ctx context.Context, id uuid.UUID) (*domain.RiskPolicy, error) {
+	return s.policyRepo.GetByID(ctx, id)
+}
+
+func (s *RiskService) GetAllPolicies(ctx context.Context) ([]*domain.RiskPolicy, error) {
+	return s.policyRepo.GetAll(ctx)
+}
+
+// UpdatePolicy replaces a policy. Scans already scored with it keep their
+// risk until they are recomputed.
+func (s *RiskService) UpdatePolicy(ctx context.Context, id uuid.UUID, req *domain.CreateRiskPolicyRequest) error {
+	policy, err := s.policyRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	update, err := s.newRiskPolicy(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPolicyName(ctx, id, update.Name); err != nil {
+		return err
+	}
+
+	update.ID = policy.ID
+	update.CreatedAt = policy.CreatedAt
+
+	if err := s.policyRepo.Update(ctx, update); err != nil {
+		return fmt.Errorf("failed to update risk policy: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePolicy removes a policy no connection is assigned to.
+func (s *RiskService) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	connections, err := s.dbConnRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connections: %w", err)
+	}
+	for _, conn := range connections {
+		if conn.RiskPolicyID != nil && *conn.RiskPolicyID == id {
+			return fmt.Errorf("risk policy is assigned to database connection %s", conn.ID)
+		}
+	}
+
+	return s.policyRepo.Delete(ctx, id)
+}
+
+// RecomputeRisk scores the completed scans among the latest req.Limit scans
+// of a database under req.PolicyID, the connection's policy, or when neither
+// is set the built-in calculation, without rescanning. The new risk replaces
+// the stored one only if req.Apply is set.
+func (s *RiskService) RecomputeRisk(ctx context.Context, databaseID uuid.UUID, req *domain.RecomputeRiskRequest) ([]*domain.RiskRecomputation, error) {
+	conn, err := s.dbConnRepo.GetByID(ctx, databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	policyID := req.PolicyID
+	if policyID == nil {
+		policyID = conn.RiskPolicyID
+	}
+	var policy *domain.RiskPolicy
+	if policyID != nil {
+		policy, err = s.policyRepo.GetByID(ctx, *policyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get risk policy: %w", err)
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRecomputeLimit
+	}
+	scans, err := s.scanRepo.GetByDatabaseID(ctx, databaseID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan history: %w", err)
+	}
+
+	results := make([]*domain.RiskRecomputation, 0, len(scans))
+	for _, scan := range scans {
+		if scan.Status != domain.ScanStatusCompleted && scan.Status != domain.ScanStatusCompletedWithErrors {
+			continue
+		}
+
+		result := &domain.RiskRecomputation{
+			ScanID:            scan.ID,
+			StartedAt:         scan.StartedAt,
+			PreviousRiskLevel: scan.Summary.RiskLevel,
+			PreviousRiskScore: scan.Summary.RiskScore,
+			PreviousPolicy:    scan.Summary.RiskPolicy,
+		}
+
+		assessRisk(scan, policy, s.classificationSvc)
+		result.RiskLevel = scan.Summary.RiskLevel
+		result.RiskScore = scan.Summary.RiskScore
+
+		if req.Apply {
+			if err := s.scanRepo.UpdateRisk(ctx, scan); err != nil {
+				return results, fmt.Errorf("failed to update risk of scan %s: %w", scan.ID, err)
+			}
+			result.Applied = true
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (s *RiskService) checkPolicyName(ctx context.Context, id uuid.UUID, name string) error {
+	policies, err := s.policyRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if policy.ID != id && strings.EqualFold(policy.Name, name) {
+			return fmt.Errorf("risk policy %s already exists", policy.Name)
+		}
+	}
+	return nil
+}
+
+// newRiskPolicy validates a policy request: weights are not negative and name
+// catalog types and tiers, the thresholds rise from medium to critical.
+func (s *RiskService) newRiskPolicy(ctx context.Context, req *domain.CreateRiskPolicyRequest) (*domain.RiskPolicy, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("risk policy name is required")
+	}
+
+	for infoType, weight := range req.TypeWeights {
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of %s must not be negative", infoType)
+		}
+		if _, err := s.classificationSvc.GetInformationType(ctx, infoType); err != nil {
+			return nil, fmt.Errorf("unknown information type %q", infoType)
+		}
+	}
+	for tier, weight := range req.TierWeights {
+		switch tier {
+		case domain.SensitivityHigh, domain.SensitivityMedium, domain.SensitivityLow:
+		default:
+			return nil, fmt.Errorf("unknown sensitivity tier '%s'", tier)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of %s sensitivity must not be negative", tier)
+		}
+	}
+
+	if req.MinConfidence < 0 || req.MinConfidence > 1 {
+		return nil, fmt.Errorf("min_confidence must be between 0 and 1")
+	}
+	for _, aggregation := range []domain.RiskAggregation{req.TableAggregation, req.DatabaseAggregation} {
+		switch aggregation {
+		case domain.RiskAggregationMax, domain.RiskAggregationSum, domain.RiskAggregationAverage:
+		default:
+			return nil, fmt.Errorf("unknown risk aggregation '%s'", aggregation)
+		}
+	}
+
+	thresholds := req.Thresholds
+	if thresholds.Medium <= 0 || thresholds.High < thresholds.Medium || thresholds.Critical < thresholds.High {
+		return nil, fmt.Errorf("risk thresholds must satisfy 0 < medium <= high <= critical")
+	}
+
+	now := time.Now().UTC()
+	return &domain.RiskPolicy{
+		Name:                name,
+		Description:         req.Description,
+		TypeWeights:         req.TypeWeights,
+		TierWeights:         req.TierWeights,
+		MinConfidence:       req.MinConfidence,
+		TableAggregation:    req.TableAggregation,
+		DatabaseAggregation: req.DatabaseAggregation,
+		Thresholds:          thresholds,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+// diffHistoryLimit bounds how far back DiffScans looks for a default scan to
+// compare against.
+const diffHistoryLimit = 50
+
+// DiffScans compares two completed scans of a database. When toScanID is nil
+// the latest completed scan is used; when fromScanID is nil the completed scan
+// that precedes the "to" scan is used. Scans whose scope was overridden by the
+// scan request are only compared when asked for by ID. The "from" scan must
+// have started before the "to" scan.
+func (s *ScanService) DiffScans(ctx context.Context, databaseID uuid.UUID, fromScanID, toScanID *uuid.UUID) (*domain.ScanDiff, error) {
+	var from, to *domain.ScanResult
+	var err error
+
+	if toScanID != nil {
+		if to, err = s.completedScan(ctx, databaseID, *toScanID); err != nil {
+			return nil, err
+		}
+	}
+	if fromScanID != nil {
+		if from, err = s.completedScan(ctx, databaseID, *fromScanID); err != nil {
+			return nil, err
+		}
+	}
+
+	if from == nil || to == nil {
+		history, err := s.scanRepo.GetByDatabaseID(ctx, databaseID, diffHistoryLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scan history: %w", err)
+		}
+
+		for _, scan := range history {
+			if !isCompletedStatus(scan.Status) || scan.ScopeOverridden {
+				continue
+			}
+			if to == nil {
+				to = scan
+				continue
+			}
+			if from == nil && scan.ID != to.ID && scan.StartedAt.Before(to.StartedAt) {
+				from = scan
+			}
+			if from != nil {
+				break
+			}
+		}
+	}
+
+	if to == nil {
+		return nil, fmt.Errorf("no completed scan found for database: %w", domain.ErrScanNotFound)
+	}
+	if from == nil {
+		return nil, fmt.Errorf("no earlier completed scan to compare against: %w", domain.ErrScanNotFound)
+	}
+	if !from.StartedAt.Before(to.StartedAt) {
+		return nil, fmt.Errorf("%w: scan %s did not start before scan %s", domain.ErrInvalidScanDiff, from.ID, to.ID)
+	}
+
+	return diffScanResults(from, to), nil
+}
+
+func (s *ScanService) completedScan(ctx context.Context, databaseID, scanID uuid.UUID) (*domain.ScanResult, error) {
+	scan, err := s.scanRepo.GetByID(ctx, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan result: %w", err)
+	}
+	if scan.DatabaseID != databaseID {
+		return nil, fmt.Errorf("scan %s does not belong to database %s: %w", scanID, databaseID, domain.ErrScanNotFound)
+	}
+	if !isCompletedStatus(scan.Status) {
+		return nil, fmt.Errorf("%w: scan %s is not completed, current status: %s", domain.ErrInvalidScanDiff, scanID, scan.Status)
+	}
+	return scan, nil
+}
+
+func diffScanResults(from, to *domain.ScanResult) *domain.ScanDiff {
+	diff := &domain.ScanDiff{
+		DatabaseID:      to.DatabaseID,
+		FromScanID:      from.ID,
+		ToScanID:        to.ID,
+		FromCompletedAt: from.CompletedAt,
+		ToCompletedAt:   to.CompletedAt,
+		AddedSchemas:    []string{},
+		RemovedSchemas:  []string{},
+		AddedTables:     []domain.TableRef{},
+		RemovedTables:   []domain.TableRef{},
+		AddedColumns:    []domain.ColumnChange{},
+		RemovedColumns:  []domain.ColumnChange{},
+		ChangedColumns:  []domain.ColumnChange{},
+	}
+
+	oldSchemas := indexSchemas(from.Schemas)
+	newSchemas := indexSchemas(to.Schemas)
+
+	// Objects a scan skipped because of errors are neither added nor removed
+	oldSkipped := skippedObjects(from.Errors)
+	newSkipped := skippedObjects(to.Errors)
+
+	for _, schema := range to.Schemas {
+		if oldSkipped[domain.TableRef{SchemaName: schema.SchemaName}] {
+			continue
+		}
+
+		oldTables, existed := oldSchemas[schema.SchemaName]
+		if !existed {
+			diff.AddedSchemas = append(diff.AddedSchemas, schema.SchemaName)
+		}
+
+		for _, table := range schema.Tables {
+			if oldSkipped[domain.TableRef{SchemaName: schema.SchemaName, TableName: table.TableName}] {
+				continue
+			}
+
+			oldColumns, existed := oldTables[table.TableName]
+			if !existed {
+				diff.AddedTables = append(diff.AddedTables, domain.TableRef{SchemaName: schema.SchemaName, TableName: table.TableName})
+			}
+
+			for _, column := range table.Columns {
+				oldColumn, existed := oldColumns[column.ColumnName]
+				if !existed {
+					diff.AddedColumns = append(diff.AddedColumns, domain.ColumnChange{
+						SchemaName:        schema.SchemaName,
+						TableName:         table.TableName,
+						ColumnName:        column.ColumnName,
+						ToInformationType: column.InformationType,
+						ToConfidenceScore: column.ConfidenceScore,
+					})
+					if isSensitive(column.InformationType) {
+						diff.NewlyExposedColumns++
+					}
+					continue
+				}
+
+				if oldColumn.InformationType != column.InformationType {
+					diff.ChangedColumns = append(diff.ChangedColumns, domain.ColumnChange{
+						SchemaName:          schema.SchemaName,
+						TableName:           table.TableName,
+						ColumnName:          column.ColumnName,
+						FromInformationType: oldColumn.InformationType,
+						ToInformationType:   column.InformationType,
+						FromConfidenceScore: oldColumn.ConfidenceScore,
+						ToConfidenceScore:   column.ConfidenceScore,
+					})
+					if !isSensitive(oldColumn.InformationType) && isSensitive(column.InformationType) {
+						diff.NewlyExposedColumns++
+					}
+				}
+			}
+		}
+	}
+
+	for _, schema := range from.Schemas {
+		if newSkipped[domain.TableRef{SchemaName: schema.SchemaName}] {
+			continue
+		}
+
+		newTables, exists := newSchemas[schema.SchemaName]
+		if !exists {
+			diff.RemovedSchemas = append(diff.RemovedSchemas, schema.SchemaName)
+		}
+
+		for _, table := range schema.Tables {
+			if newSkipped[domain.TableRef{SchemaName: schema.SchemaName, TableName: table.TableName}] {
+				continue
+			}
+
+			newColumns, exists := newTables[table.TableName]
+			if !exists {
+				diff.RemovedTables = append(diff.RemovedTables, domain.TableRef{SchemaName: schema.SchemaName, TableName: table.TableName})
+			}
+
+			for _, column := range table.Columns {
+				if _, exists := newColumns[column.ColumnName]; !exists {
+					diff.RemovedColumns = append(diff.RemovedColumns, domain.ColumnChange{
+						SchemaName:          schema.SchemaName,
+						TableName:           table.TableName,
+						ColumnName:          column.ColumnName,
+						FromInformationType: column.InformationType,
+						FromConfidenceScore: column.ConfidenceScore,
+					})
+				}
+			}
+		}
+	}
+
+	if from.Summary.RiskLevel != to.Summary.RiskLevel {
+		diff.RiskLevelChange = &domain.RiskLevelChange{
+			From: from.Summary.RiskLevel,
+			To:   to.Summary.RiskLevel,
+		}
+	}
+
+	diff.HasChanges = len(diff.AddedColumns) > 0 || len(diff.RemovedColumns) > 0 ||
+		len(diff.ChangedColumns) > 0 || len(diff.AddedTables) > 0 || len(diff.RemovedTables) > 0 ||
+		len(diff.AddedSchemas) > 0 || len(diff.RemovedSchemas) > 0 || diff.RiskLevelChange != nil
+
+	return diff
+}
+
+// indexSchemas maps schema name to table name to column name.
+func indexSchemas(schemas []domain.SchemaResult) map[string]map[string]map[string]domain.ColumnResult {
+	index := make(map[string]map[string]map[string]domain.ColumnResult, len(schemas))
+	for _, schema := range schemas {
+		tables := make(map[string]map[string]domain.ColumnResult, len(schema.Tables))
+		for _, table := range schema.Tables {
+			columns := make(map[string]domain.ColumnResult, len(table.Columns))
+			for _, column := range table.Columns {
+				columns[column.ColumnName] = column
+			}
+			tables[table.TableName] = columns
+		}
+		index[schema.SchemaName] = tables
+	}
+	return index
+}
+
+// skippedObjects returns the schemas, as refs without a table name, and the
+// tables a scan left out because of errors.
+func skippedObjects(scanErrors []domain.ScanError) map[domain.TableRef]bool {
+	skipped := make(map[domain.TableRef]bool)
+	for _, scanErr := range scanErrors {
+		switch scanErr.Phase {
+		case domain.ScanPhaseTables:
+			skipped[domain.TableRef{SchemaName: scanErr.SchemaName}] = true
+		case domain.ScanPhaseColumns:
+			skipped[domain.TableRef{SchemaName: scanErr.SchemaName, TableName: scanErr.TableName}] = true
+		}
+	}
+	return skipped
+}
+
+func isCompletedStatus(status domain.ScanStatus) bool {
+	return status == domain.ScanStatusCompleted || status == domain.ScanStatusCompletedWithErrors
+}
+
+func isSensitive(infoType domain.InformationType) bool {
+	return infoType != "" && infoType != domain.InfoTypeNA
+}
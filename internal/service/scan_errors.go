@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"database-classifier/internal/domain"
+	"database-classifier/internal/infrastructure/database"
+)
+
+// scanErrorLog collects the schema- and table-level errors of a scan that
+// tolerates them. A nil log means errors are not tolerated.
+type scanErrorLog struct {
+	mu     sync.Mutex
+	errors []domain.ScanError
+	lost   error
+}
+
+// tolerate records err against schema and table and reports whether the scan
+// may go on without them. Connection failures and cancellation are never
+// tolerated; a lost connection is kept so that the scan can fail with it.
+func (l *scanErrorLog) tolerate(ctx context.Context, schema, table string, phase domain.ScanPhase, err error) bool {
+	if l == nil || ctx.Err() != nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if database.IsConnectionError(err) {
+		if l.lost == nil {
+			l.lost = err
+		}
+		return false
+	}
+
+	l.errors = append(l.errors, domain.ScanError{
+		SchemaName: schema,
+		TableName:  table,
+		Phase:      phase,
+		Message:    err.Error(),
+	})
+	return true
+}
+
+// connectionLost returns the first connection failure seen while tolerating
+// errors, if any.
+func (l *scanErrorLog) connectionLost() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lost
+}
+
+// list returns the recorded errors ordered by schema, table and phase, since
+// sampling errors are recorded in whatever order the workers finish.
+func (l *scanErrorLog) list() []domain.ScanError {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	scanErrors := append([]domain.ScanError(nil), l.errors...)
+	sort.SliceStable(scanErrors, func(i, j int) bool {
+		a, b := scanErrors[i], scanErrors[j]
+		if a.SchemaName != b.SchemaName {
+			return a.SchemaName < b.SchemaName
+		}
+		if a.TableName != b.TableName {
+			return a.TableName < b.TableName
+		}
+		return a.Phase < b.Phase
+	})
+	return scanErrors
+}
+
+// countSkipped returns the schemas and tables left out of a scan because of
+// its errors. Sampling errors do not skip the table.
+func countSkipped(scanErrors []domain.ScanError) (schemas, tables int) {
+	for _, scanErr := range scanErrors {
+		switch scanErr.Phase {
+		case domain.ScanPhaseTables:
+			schemas++
+		case domain.ScanPhaseColumns:
+			tables++
+		}
+	}
+	return schemas, tables
+}
+
+// readTablesOneByOne reads a schema whose catalog could not be read in bulk
+// table by table, so that one unreadable table only skips itself. It returns
+// the tables in scope that could be read and whether the schema was skipped.
+func readTablesOneByOne(ctx context.Context, inspector domain.Inspector, scope *scopeMatcher, errs *scanErrorLog, schemaName string) ([]*domain.TableInfo, bool, error) {
+	names, err := inspector.GetTables(ctx, schemaName)
+	if err != nil {
+		if errs.tolerate(ctx, schemaName, "", domain.ScanPhaseTables, err) {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("failed to get tables for schema %s: %w", schemaName, err)
+	}
+
+	var tables []*domain.TableInfo
+	for _, tableName := range names {
+		if !scope.table(tableName) {
+			continue
+		}
+
+		tableInfo, err := inspector.GetTableInfo(ctx, schemaName, tableName)
+		if err != nil {
+			if errs.tolerate(ctx, schemaName, tableName, domain.ScanPhaseColumns, err) {
+				continue
+			}
+			return nil, false, fmt.Errorf("failed to get columns for table %s.%s: %w", schemaName, tableName, err)
+		}
+		tables = append(tables, tableInfo)
+	}
+
+	return tables, false, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+// baseScan indexes the tables of the completed scan an incremental scan
+// reuses unchanged tables from. A nil baseScan reuses nothing.
+type baseScan struct {
+	id     uuid.UUID
+	tables map[domain.TableRef]domain.TableResult
+}
+
+// loadBaseScan returns the latest completed scan of a database, or nil when
+// there is none and the incremental scan has to scan every table.
+func (s *ScanService) loadBaseScan(ctx context.Context, databaseID uuid.UUID) *baseScan {
+	previous, err := s.scanRepo.GetLatestByDatabaseID(ctx, databaseID)
+	if err != nil {
+		fmt.Printf("Warning: no base scan for incremental scan of database %s, scanning every table: %v\n", databaseID, err)
+		return nil
+	}
+
+	// Tables whose sampling failed were classified by name only
+	unsampled := make(map[domain.TableRef]bool)
+	for _, scanErr := range previous.Errors {
+		if scanErr.Phase == domain.ScanPhaseSample {
+			unsampled[domain.TableRef{SchemaName: scanErr.SchemaName, TableName: scanErr.TableName}] = true
+		}
+	}
+
+	base := &baseScan{
+		id:     previous.ID,
+		tables: make(map[domain.TableRef]domain.TableResult),
+	}
+	for _, schema := range previous.Schemas {
+		for _, table := range schema.Tables {
+			ref := domain.TableRef{SchemaName: schema.SchemaName, TableName: table.TableName}
+			if !unsampled[ref] {
+				base.tables[ref] = table
+			}
+		}
+	}
+
+	return base
+}
+
+// reuse returns the base scan's result for tableInfo if neither its columns,
+// its data, the pattern packs it is classified with nor the classifier
+// configuration have changed since.
+func (b *baseScan) reuse(tableInfo *domain.TableInfo, sampleSize int, packs []string, classifierVersion string) (domain.TableResult, bool) {
+	if b == nil || tableInfo.DataVersion == "" {
+		return domain.TableResult{}, false
+	}
+
+	previous, ok := b.tables[domain.TableRef{SchemaName: tableInfo.SchemaName, TableName: tableInfo.TableName}]
+	if !ok || previous.DataVersion != tableInfo.DataVersion || previous.Fingerprint != tableFingerprint(tableInfo, sampleSize, packs, classifierVersion) {
+		return domain.TableResult{}, false
+	}
+
+	previous.Reused = true
+	return previous, true
+}
+
+// tableFingerprint hashes the columns and comment of a table as they are
+// classified, along with the sample size, which changes the value evidence
+// gathered, the enabled pattern packs, which change the patterns matched, and
+// the version of the patterns, abbreviations and information types the
+// classifier was loaded with.
+func tableFingerprint(tableInfo *domain.TableInfo, sampleSize int, packs []string, classifierVersion string) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.Itoa(sampleSize)))
+	h.Write([]byte{0})
+	h.Write([]byte(tableInfo.Comment))
+	for _, column := range tableInfo.Columns {
+		defaultValue := "\x00"
+		if column.DefaultValue != nil {
+			defaultValue = *column.DefaultValue
+		}
+		maxLength := "\x00"
+		if column.MaxLength != nil {
+			maxLength = strconv.FormatInt(*column.MaxLength, 10)
+		}
+		for _, field := range []string{column.ColumnName, column.DataType, strconv.FormatBool(column.IsNullable), defaultValue, column.ColumnKey, maxLength, column.Comment} {
+			h.Write([]byte{0})
+			h.Write([]byte(field))
+		}
+	}
+	if len(packs) > 0 {
+		h.Write([]byte{0})
+		h.Write([]byte("packs:" + strings.Join(packs, ",")))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte("classifier:" + classifierVersion))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
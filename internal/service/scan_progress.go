@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+// watchEventBuffer is how many events a watcher of a local scan may fall
+// behind before its oldest undelivered events are dropped.
+const watchEventBuffer = 256
+
+// watchPollInterval is how often a watcher of a scan that is pending or runs
+// on another replica re-reads the scan from the metadata database.
+const watchPollInterval = 2 * time.Second
+
+// progressTracker holds the progress of a scan running in this process and
+// fans its events out to the watchers subscribed on this replica.
+type progressTracker struct {
+	scanID uuid.UUID
+
+	mu        sync.Mutex
+	progress  domain.ScanProgress
+	startedAt time.Time
+	changed   bool
+	watchers  map[chan domain.ScanEvent]struct{}
+	final     *domain.ScanEvent
+}
+
+func newProgressTracker(scanID uuid.UUID) *progressTracker {
+	return &progressTracker{
+		scanID:   scanID,
+		progress: domain.ScanProgress{UpdatedAt: time.Now().UTC()},
+		watchers: make(map[chan domain.ScanEvent]struct{}),
+	}
+}
+
+// setTotals records the schemas and tables in scope once their catalogs have
+// been read; the ETA is measured from this point.
+func (t *progressTracker) setTotals(schemas, tables int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.startedAt = time.Now()
+	t.progress.SchemasTotal = schemas
+	t.progress.TablesTotal = tables
+	t.touch()
+	t.publish(t.event(domain.ScanEventProgress))
+}
+
+func (t *progressTracker) startTable(schema, table string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.CurrentSchema = schema
+	t.progress.CurrentTable = table
+	t.touch()
+}
+
+// finishTable counts a classified table and streams its findings along with
+// the updated progress.
+func (t *progressTracker) finishTable(schema string, result domain.TableResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.TablesProcessed++
+	if remaining := t.progress.TablesTotal - t.progress.TablesProcessed; remaining >= 0 {
+		perTable := time.Since(t.startedAt) / time.Duration(t.progress.TablesProcessed)
+		eta := int64((perTable * time.Duration(remaining)).Round(time.Second).Seconds())
+		t.progress.ETASeconds = &eta
+	}
+	t.touch()
+
+	event := t.event(domain.ScanEventTable)
+	event.SchemaName = schema
+	event.Table = &result
+	t.publish(event)
+}
+
+func (t *progressTracker) finishSchema() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.SchemasProcessed++
+	t.touch()
+	t.publish(t.event(domain.ScanEventProgress))
+}
+
+// snapshot returns a copy of the progress and whether it changed since the
+// previous snapshot.
+func (t *progressTracker) snapshot() (*domain.ScanProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress := t.copyProgress()
+	changed := t.changed
+	t.changed = false
+	return progress, changed
+}
+
+// finish sends the final event of the scan to every watcher and closes their
+// channels. Watchers subscribing afterwards only receive that event.
+func (t *progressTracker) finish(status domain.ScanStatus, errorMessage string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	event := t.event(domain.ScanEventDone)
+	event.Status = status
+	event.ErrorMessage = errorMessage
+	t.final = &event
+
+	for ch := range t.watchers {
+		offer(ch, event)
+		close(ch)
+		delete(t.watchers, ch)
+	}
+}
+
+// subscribe returns a channel of the scan's events, starting with its current
+// progress, and a function that stops the subscription.
+func (t *progressTracker) subscribe() (<-chan domain.ScanEvent, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ch := make(chan domain.ScanEvent, watchEventBuffer)
+	if t.final != nil {
+		ch <- *t.final
+		close(ch)
+		return ch, func() {}
+	}
+
+	ch <- t.event(domain.ScanEventProgress)
+	t.watchers[ch] = struct{}{}
+
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.watchers[ch]; ok {
+			delete(t.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (t *progressTracker) touch() {
+	t.progress.UpdatedAt = time.Now().UTC()
+	t.changed = true
+}
+
+func (t *progressTracker) copyProgress() *domain.ScanProgress {
+	progress := t.progress
+	if progress.ETASeconds != nil {
+		eta := *progress.ETASeconds
+		progress.ETASeconds = &eta
+	}
+	return &progress
+}
+
+func (t *progressTracker) event(eventType domain.ScanEventType) domain.ScanEvent {
+	return domain.ScanEvent{
+		Type:     eventType,
+		ScanID:   t.scanID,
+		Status:   domain.ScanStatusRunning,
+		Progress: t.copyProgress(),
+	}
+}
+
+func (t *progressTracker) publish(event domain.ScanEvent) {
+	for ch := range t.watchers {
+		offer(ch, event)
+	}
+}
+
+// offer delivers event without blocking the scan, dropping the oldest queued
+// event of a watcher that has fallen behind.
+func offer(ch chan domain.ScanEvent, event domain.ScanEvent) {
+	for {
+		select {
+		case ch <- event:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
+
+func (s *ScanService) trackProgress(scanID uuid.UUID) *progressTracker {
+	tracker := newProgressTracker(scanID)
+	s.mu.Lock()
+	s.trackers[scanID] = tracker
+	s.mu.Unlock()
+	return tracker
+}
+
+func (s *ScanService) untrackProgress(scanID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.trackers, scanID)
+	s.mu.Unlock()
+}
+
+func (s *ScanService) localTracker(scanID uuid.UUID) *progressTracker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trackers[scanID]
+}
+
+// finishProgress ends the event stream of a scan with the status recorded for
+// it, whichever way the scan ended.
+func (s *ScanService) finishProgress(tracker *progressTracker) {
+	status, errorMessage := domain.ScanStatusFailed, ""
+	if result, err := s.scanRepo.GetByID(context.Background(), tracker.scanID); err == nil {
+		status, errorMessage = result.Status, result.ErrorMessage
+	} else {
+		errorMessage = err.Error()
+	}
+	tracker.finish(status, errorMessage)
+}
+
+// WatchScan streams the events of a scan until it ends or ctx is done. A scan
+// running in this process reports every classified table; for a scan that is
+// pending or runs on another replica only its persisted progress is polled,
+// and the stream switches to table events if the scan starts here.
+func (s *ScanService) WatchScan(ctx context.Context, scanID uuid.UUID) (<-chan domain.ScanEvent, error) {
+	result, err := s.scanRepo.GetByID(ctx, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan result: %w", err)
+	}
+
+	events := make(chan domain.ScanEvent)
+	go s.watch(ctx, result, events)
+
+	return events, nil
+}
+
+func (s *ScanService) watch(ctx context.Context, result *domain.ScanResult, events chan<- domain.ScanEvent) {
+	defer close(events)
+
+	send := func(event domain.ScanEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	var lastStatus domain.ScanStatus
+	var lastUpdate time.Time
+	for {
+		if isFinalStatus(result.Status) {
+			send(domain.ScanEvent{
+				Type:         domain.ScanEventDone,
+				ScanID:       result.ID,
+				Progress:     result.Progress,
+				Status:       result.Status,
+				ErrorMessage: result.ErrorMessage,
+			})
+			return
+		}
+
+		if tracker := s.localTracker(result.ID); tracker != nil {
+			s.forward(ctx, tracker, send)
+			return
+		}
+
+		updated := result.Progress != nil && result.Progress.UpdatedAt.After(lastUpdate)
+		if result.Status != lastStatus || updated {
+			if !send(domain.ScanEvent{
+				Type:     domain.ScanEventProgress,
+				ScanID:   result.ID,
+				Progress: result.Progress,
+				Status:   result.Status,
+			}) {
+				return
+			}
+			lastStatus = result.Status
+			if result.Progress != nil {
+				lastUpdate = result.Progress.UpdatedAt
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		next, err := s.scanRepo.GetByID(ctx, result.ID)
+		if err != nil {
+			fmt.Printf("Warning: failed to reload watched scan %s: %v\n", result.ID, err)
+			continue
+		}
+		result = next
+	}
+}
+
+func (s *ScanService) forward(ctx context.Context, tracker *progressTracker, send func(domain.ScanEvent) bool) {
+	ch, unsubscribe := tracker.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if !ok || !send(event) || event.Type == domain.ScanEventDone {
+				return
+			}
+		}
+	}
+}
+
+func isFinalStatus(status domain.ScanStatus) bool {
+	return status == domain.ScanStatusCompleted || status == domain.ScanStatusCompletedWithErrors ||
+		status == domain.ScanStatusFailed || status == domain.ScanStatusCancelled
+}
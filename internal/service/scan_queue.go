@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"database-classifier/internal/domain"
+)
+
+// queuePollInterval bounds how long an idle worker waits before looking for
+// jobs enqueued by other API replicas.
+const queuePollInterval = 2 * time.Second
+
+const orphanedScanMessage = "Scan interrupted: the API instance running it stopped before it finished"
+
+type ScanQueueOptions struct {
+	// WorkerID identifies this API instance in the claimed_by column.
+	WorkerID string
+	// MaxConcurrent caps the scans running at once across all replicas; it is
+	// also the number of workers started in this process.
+	MaxConcurrent int
+	// MaxPerTarget caps the scans running at once against the same server.
+	MaxPerTarget int
+	// TableConcurrency is the number of tables a scan samples and classifies
+	// at once, unless its connection sets ScanConcurrency.
+	TableConcurrency int
+	// TolerateTableErrors makes scans record the schemas and tables they
+	// cannot read and complete without them; connection failures still fail
+	// the scan.
+	TolerateTableErrors bool
+	// RecoveryPolicy and StaleAfter are applied every StaleAfter to scans
+	// orphaned by a replica that stopped while running them, so that their
+	// databases can be scanned again; see RecoverOrphanedScans.
+	RecoveryPolicy domain.RecoveryPolicy
+	StaleAfter     time.Duration
+}
+
+// StartWorkers launches the worker pool that drains the scan queue, along
+// with the periodic recovery of orphaned scans. Workers stop claiming new
+// jobs once ctx is done; scans already running are left to finish or to be
+// recovered by another replica or on the next start.
+func (s *ScanService) StartWorkers(ctx context.Context) {
+	for i := 0; i < s.queue.MaxConcurrent; i++ {
+		go s.worker(ctx)
+	}
+	if s.queue.StaleAfter > 0 {
+		go s.recoverOrphans(ctx)
+	}
+}
+
+// recoverOrphans runs RecoverOrphanedScans every StaleAfter. Every replica
+// runs it; ReclaimStale lets only one of them reclaim a given scan.
+func (s *ScanService) recoverOrphans(ctx context.Context) {
+	ticker := time.NewTicker(s.queue.StaleAfter)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.RecoverOrphanedScans(ctx, s.queue.RecoveryPolicy, s.queue.StaleAfter)
+			if err != nil {
+				fmt.Printf("Warning: failed to recover orphaned scans: %v\n", err)
+				continue
+			}
+			if report.Found > 0 {
+				fmt.Printf("Recovered %d orphaned scans (policy=%s, failed=%d, requeued=%d)\n", report.Found, report.Policy, report.Failed, report.Requeued)
+			}
+		}
+	}
+}
+
+func (s *ScanService) worker(ctx context.Context) {
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.processNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ScanService) notifyWorkers() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// processNext claims and runs one job. It reports whether a job was claimed so
+// the worker keeps draining the queue without waiting for the next tick.
+func (s *ScanService) processNext(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	job, err := s.jobRepo.Claim(ctx, s.queue.WorkerID, s.queue.MaxConcurrent, s.queue.MaxPerTarget)
+	if err != nil {
+		fmt.Printf("Warning: failed to claim scan job: %v\n", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	defer func() {
+		if err := s.jobRepo.Delete(context.Background(), job.ScanID); err != nil {
+			fmt.Printf("Warning: failed to remove finished scan job %s: %v\n", job.ScanID, err)
+		}
+	}()
+
+	scanResult, err := s.scanRepo.GetByID(ctx, job.ScanID)
+	if err != nil {
+		fmt.Printf("Warning: failed to load queued scan %s: %v\n", job.ScanID, err)
+		return true
+	}
+
+	conn, err := s.dbConnRepo.GetByID(ctx, job.DatabaseID)
+	if err != nil {
+		message := fmt.Sprintf("failed to get database connection: %v", err)
+		if _, err := s.scanRepo.TransitionStatus(ctx, job.ScanID, domain.ScanStatusFailed, message, domain.ScanStatusPending); err != nil {
+			fmt.Printf("Warning: failed to mark scan %s as failed: %v\n", job.ScanID, err)
+		}
+		return true
+	}
+
+	s.run(scanResult, conn)
+	return true
+}
+
+func scanTarget(conn *domain.DatabaseConnection) string {
+	// A SQLite file has no server to protect; each connection is its own target
+	if conn.Engine == domain.EngineSQLite {
+		return "sqlite:" + conn.ID.String()
+	}
+	return fmt.Sprintf("%s:%d", strings.ToLower(conn.Host), conn.Port)
+}
+
+// RecoverOrphanedScans finds scans that were claimed by an API instance which
+// has not reported on them for longer than staleAfter and, depending on policy,
+// marks them failed or puts them back in the queue. Scans still waiting in the
+// queue are not orphaned and are left alone.
+func (s *ScanService) RecoverOrphanedScans(ctx context.Context, policy domain.RecoveryPolicy, staleAfter time.Duration) (*domain.RecoveryReport, error) {
+	if policy != domain.RecoveryPolicyFail && policy != domain.RecoveryPolicyRequeue {
+		return nil, fmt.Errorf("unknown scan recovery policy: %s", policy)
+	}
+
+	scans, err := s.scanRepo.GetRunningScans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running scans: %w", err)
+	}
+
+	jobs, err := s.jobRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan jobs: %w", err)
+	}
+	jobsByScan := make(map[uuid.UUID]*domain.ScanJob, len(jobs))
+	for _, job := range jobs {
+		jobsByScan[job.ScanID] = job
+	}
+
+	report := &domain.RecoveryReport{Policy: policy}
+	staleBefore := time.Now().UTC().Add(-staleAfter)
+
+	for _, scan := range scans {
+		job, queued := jobsByScan[scan.ID]
+		if queued && job.ClaimedBy == "" {
+			continue
+		}
+
+		lastSeen := scan.StartedAt
+		if scan.HeartbeatAt != nil {
+			lastSeen = *scan.HeartbeatAt
+		}
+		if queued && job.ClaimedAt != nil && job.ClaimedAt.After(lastSeen) {
+			lastSeen = *job.ClaimedAt
+		}
+		if !lastSeen.Before(staleBefore) {
+			continue
+		}
+		report.Found++
+
+		if policy == domain.RecoveryPolicyRequeue {
+			conn, err := s.dbConnRepo.GetByID(ctx, scan.DatabaseID)
+			if err == nil {
+				status, err := s.requeueOrphan(ctx, scan, conn, staleBefore)
+				if err != nil {
+					return report, err
+				}
+				switch status {
+				case domain.ScanStatusPending:
+					report.Requeued++
+				case domain.ScanStatusFailed:
+					report.Failed++
+				}
+				continue
+			}
+			fmt.Printf("Warning: cannot requeue scan %s, marking it failed: %v\n", scan.ID, err)
+		}
+
+		reclaimed, err := s.scanRepo.ReclaimStale(ctx, scan.ID, staleBefore, domain.ScanStatusFailed, orphanedScanMessage)
+		if err != nil {
+			return report, err
+		}
+		if reclaimed {
+			if err := s.jobRepo.Delete(ctx, scan.ID); err != nil {
+				return report, err
+			}
+			report.Failed++
+		}
+	}
+
+	if report.Requeued > 0 {
+		s.notifyWorkers()
+	}
+
+	return report, nil
+}
+
+// requeueOrphan puts an orphaned scan back in the queue and returns the status
+// it ended in: pending, failed when another scan of its database already holds
+// the queue slot, or empty when another replica reclaimed it first.
+func (s *ScanService) requeueOrphan(ctx context.Context, scan *domain.ScanResult, conn *domain.DatabaseConnection, staleBefore time.Time) (domain.ScanStatus, error) {
+	reclaimed, err := s.scanRepo.ReclaimStale(ctx, scan.ID, staleBefore, domain.ScanStatusPending, "")
+	if err != nil || !reclaimed {
+		return "", err
+	}
+
+	job := &domain.ScanJob{
+		ScanID:     scan.ID,
+		DatabaseID: scan.DatabaseID,
+		Target:     scanTarget(conn),
+		EnqueuedAt: scan.StartedAt,
+	}
+	if err := s.jobRepo.Requeue(ctx, job); err != nil {
+		if !errors.Is(err, domain.ErrScanInProgress) {
+			return "", err
+		}
+		fmt.Printf("Warning: another scan of database %s is queued, marking orphaned scan %s failed\n", scan.DatabaseID, scan.ID)
+		if _, err := s.scanRepo.TransitionStatus(ctx, scan.ID, domain.ScanStatusFailed, orphanedScanMessage, domain.ScanStatusPending); err != nil {
+			return "", err
+		}
+		return domain.ScanStatusFailed, nil
+	}
+
+	return domain.ScanStatusPending, nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"database-classifier/internal/domain"
+)
+
+// dominantTypesLimit is how many information types a table or schema lists
+// as dominant.
+const dominantTypesLimit = 3
+
+// assessRisk sets the risk of a scan, of each of its schemas and of each of
+// their tables, under policy or, when it is nil, with the built-in
+// calculation.
+func assessRisk(result *domain.ScanResult, policy *domain.RiskPolicy, classificationSvc domain.ClassificationService) {
+	var tableScores []float64
+	for i := range result.Schemas {
+		assessSchemaRisk(&result.Schemas[i], policy, classificationSvc)
+		for _, table := range result.Schemas[i].Tables {
+			tableScores = append(tableScores, table.RiskScore)
+		}
+	}
+
+	summary := &result.Summary
+	if policy == nil {
+		summary.RiskLevel = calculateRiskLevel(classificationSvc, summary.InformationTypesCounts, summary.TotalColumns)
+		summary.RiskScore = 0
+		summary.RiskPolicyID = nil
+		summary.RiskPolicy = ""
+		return
+	}
+
+	policyID := policy.ID
+	summary.RiskScore = aggregateRisk(policy.DatabaseAggregation, tableScores)
+	summary.RiskLevel = riskLevel(policy, summary.RiskScore)
+	summary.RiskPolicyID = &policyID
+	summary.RiskPolicy = policy.Name
+}
+
+// assessSchemaRisk sets the risk of a schema and its tables. Under a policy a
+// schema aggregates the scores of its tables as the database does.
+func assessSchemaRisk(schema *domain.SchemaResult, policy *domain.RiskPolicy, classificationSvc domain.ClassificationService) {
+	schemaCounts := make(map[domain.InformationType]int)
+	schemaColumns := 0
+	tableScores := make([]float64, len(schema.Tables))
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+
+		counts := make(map[domain.InformationType]int)
+		columnScores := make([]float64, len(table.Columns))
+		for j, column := range table.Columns {
+			if column.InformationType != domain.InfoTypeNA && column.InformationType != "" {
+				counts[column.InformationType]++
+				schemaCounts[column.InformationType]++
+			}
+			if policy != nil {
+				columnScores[j] = columnRiskWeight(policy, classificationSvc, column)
+			}
+		}
+		schemaColumns += len(table.Columns)
+
+		table.SensitiveColumns = sensitiveColumns(classificationSvc, counts)
+		table.DominantInformationTypes = dominantInformationTypes(counts)
+		if policy == nil {
+			table.RiskScore = 0
+			table.RiskLevel = calculateRiskLevel(classificationSvc, counts, len(table.Columns))
+		} else {
+			table.RiskScore = aggregateRisk(policy.TableAggregation, columnScores)
+			table.RiskLevel = riskLevel(policy, table.RiskScore)
+		}
+		tableScores[i] = table.RiskScore
+	}
+
+	schema.SensitiveColumns = sensitiveColumns(classificationSvc, schemaCounts)
+	schema.DominantInformationTypes = dominantInformationTypes(schemaCounts)
+	if policy == nil {
+		schema.RiskScore = 0
+		schema.RiskLevel = calculateRiskLevel(classificationSvc, schemaCounts, schemaColumns)
+	} else {
+		schema.RiskScore = aggregateRisk(policy.DatabaseAggregation, tableScores)
+		schema.RiskLevel = riskLevel(policy, schema.RiskScore)
+	}
+}
+
+// sensitiveColumns counts the columns whose information type is of high or
+// medium sensitivity.
+func sensitiveColumns(classificationSvc domain.ClassificationService, infoTypeCounts map[domain.InformationType]int) int {
+	sensitive := 0
+	for infoType, count := range infoTypeCounts {
+		switch classificationSvc.Sensitivity(infoType) {
+		case domain.SensitivityHigh, domain.SensitivityMedium:
+			sensitive += count
+		}
+	}
+	return sensitive
+}
+
+// dominantInformationTypes returns the most frequent information types, ties
+// broken by name.
+func dominantInformationTypes(infoTypeCounts map[domain.InformationType]int) []domain.InformationType {
+	infoTypes := make([]domain.InformationType, 0, len(infoTypeCounts))
+	for infoType := range infoTypeCounts {
+		infoTypes = append(infoTypes, infoType)
+	}
+	sort.Slice(infoTypes, func(i, j int) bool {
+		if infoTypeCounts[infoTypes[i]] != infoTypeCounts[infoTypes[j]] {
+			return infoTypeCounts[infoTypes[i]] > infoTypeCounts[infoTypes[j]]
+		}
+		return infoTypes[i] < infoTypes[j]
+	})
+
+	if len(infoTypes) > dominantTypesLimit {
+		infoTypes = infoTypes[:dominantTypesLimit]
+	}
+	if len(infoTypes) == 0 {
+		return nil
+	}
+	return infoTypes
+}
+
+// columnRiskWeight is the weight of the information type of a column under
+// policy, falling back to the weight of its sensitivity tier.
+func columnRiskWeight(policy *domain.RiskPolicy, classificationSvc domain.ClassificationService, column domain.ColumnResult) float64 {
+	if column.InformationType == domain.InfoTypeNA || column.InformationType == "" {
+		return 0
+	}
+	if column.ConfidenceScore < policy.MinConfidence {
+		return 0
+	}
+	if weight, ok := policy.TypeWeights[column.InformationType]; ok {
+		return weight
+	}
+	return policy.TierWeights[classificationSvc.Sensitivity(column.InformationType)]
+}
+
+// aggregateRisk combines scores, rounded to four decimals so that stored
+// scores compare as written; no scores aggregate to 0.
+func aggregateRisk(aggregation domain.RiskAggregation, scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+
+	var score float64
+	switch aggregation {
+	case domain.RiskAggregationMax:
+		for _, s := range scores {
+			score = math.Max(score, s)
+		}
+	case domain.RiskAggregationSum, domain.RiskAggregationAverage:
+		for _, s := range scores {
+			score += s
+		}
+		if aggregation == domain.RiskAggregationAverage {
+			score /= float64(len(scores))
+		}
+	}
+
+	return math.Round(score*10000) / 10000
+}
+
+// riskLevel is the level of the highest threshold of policy a score reaches.
+func riskLevel(policy *domain.RiskPolicy, score float64) domain.RiskLevel {
+	switch {
+	case score >= policy.Thresholds.Critical:
+		return domain.RiskLevelCritical
+	case score >= policy.Thresholds.High:
+		return domain.RiskLevelHigh
+	case score >= policy.Thresholds.Medium:
+		return domain.RiskLevelMedium
+	}
+	return domain.RiskLevelLow
+}
+
+// calculateRiskLevel is the built-in risk calculation used for connections
+// without a risk policy.
+func calculateRiskLevel(classificationSvc domain.ClassificationService, infoTypeCounts map[domain.InformationType]int, totalColumns int) domain.RiskLevel {
+	if totalColumns == 0 {
+		return domain.RiskLevelLow
+	}
+
+	highRiskCount := 0
+	mediumRiskCount := 0
+
+	// The tiers come from the information type catalog
+	for infoType, count := range infoTypeCounts {
+		switch classificationSvc.Sensitivity(infoType) {
+		case domain.SensitivityHigh:
+			highRiskCount += count
+		case domain.SensitivityMedium:
+			mediumRiskCount += count
+		}
+	}
+
+	totalSensitiveColumns := highRiskCount + mediumRiskCount
+	riskPercentage := float64(totalSensitiveColumns) / float64(totalColumns) * 100
+
+	if highRiskCount > 0 && riskPercentage > 20 {
+		return domain.RiskLevelCritical
+	} else if highRiskCount > 0 || riskPercentage > 15 {
+		return domain.RiskLevelHigh
+	} else if mediumRiskCount > 0 || riskPercentage > 5 {
+		return domain.RiskLevelMedium
+	}
+
+	return domain.RiskLevelLow
+}
+
+// riskRanks orders risk levels; an empty level ranks lowest.
+var riskRanks = map[domain.RiskLevel]int{
+	domain.RiskLevelLow:      1,
+	domain.RiskLevelMedium:   2,
+	domain.RiskLevelHigh:     3,
+	domain.RiskLevelCritical: 4,
+}
+
+// sortByRisk orders the schemas of a scan, and the tables of each schema,
+// from most to least risky: by risk level, then risk score, then number of
+// sensitive columns. Ties keep their order.
+func sortByRisk(result *domain.ScanResult) {
+	for i := range result.Schemas {
+		tables := result.Schemas[i].Tables
+		sort.SliceStable(tables, func(a, b int) bool {
+			return riskier(tables[a].RiskLevel, tables[a].RiskScore, tables[a].SensitiveColumns, tables[b].RiskLevel, tables[b].RiskScore, tables[b].SensitiveColumns)
+		})
+	}
+
+	schemas := result.Schemas
+	sort.SliceStable(schemas, func(a, b int) bool {
+		return riskier(schemas[a].RiskLevel, schemas[a].RiskScore, schemas[a].SensitiveColumns, schemas[b].RiskLevel, schemas[b].RiskScore, schemas[b].SensitiveColumns)
+	})
+}
+
+func riskier(levelA domain.RiskLevel, scoreA float64, sensitiveA int, levelB domain.RiskLevel, scoreB float64, sensitiveB int) bool {
+	if riskRanks[levelA] != riskRanks[levelB] {
+		return riskRanks[levelA] > riskRanks[levelB]
+	}
+	if scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	return sensitiveA > sensitiveB
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"database-classifier/internal/domain"
+)
+
+// regexPatternPrefix marks a scope pattern as a regular expression rather
+// than a glob.
+const regexPatternPrefix = "re:"
+
+// scopeMatcher is a compiled ScanScope. The zero value matches everything.
+type scopeMatcher struct {
+	database       string
+	includeSchemas []*regexp.Regexp
+	excludeSchemas []*regexp.Regexp
+	includeTables  []*regexp.Regexp
+	excludeTables  []*regexp.Regexp
+	includeColumns []*regexp.Regexp
+	excludeColumns []*regexp.Regexp
+}
+
+func compileScope(scope *domain.ScanScope) (*scopeMatcher, error) {
+	matcher := &scopeMatcher{}
+	if scope == nil {
+		return matcher, nil
+	}
+	matcher.database = scope.Database
+
+	lists := []struct {
+		name     string
+		patterns []string
+		dest     *[]*regexp.Regexp
+	}{
+		{"include_schemas", scope.IncludeSchemas, &matcher.includeSchemas},
+		{"exclude_schemas", scope.ExcludeSchemas, &matcher.excludeSchemas},
+		{"include_tables", scope.IncludeTables, &matcher.includeTables},
+		{"exclude_tables", scope.ExcludeTables, &matcher.excludeTables},
+		{"include_columns", scope.IncludeColumns, &matcher.includeColumns},
+		{"exclude_columns", scope.ExcludeColumns, &matcher.excludeColumns},
+	}
+
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			re, err := compileScopePattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s pattern %q: %v", domain.ErrInvalidScanScope, list.name, pattern, err)
+			}
+			*list.dest = append(*list.dest, re)
+		}
+	}
+
+	return matcher, nil
+}
+
+func compileScopePattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, regexPatternPrefix); ok {
+		return regexp.Compile(expr)
+	}
+
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
+
+func (m *scopeMatcher) schema(name string) bool {
+	if m.database != "" && name != m.database {
+		return false
+	}
+	return matchScope(name, m.includeSchemas, m.excludeSchemas)
+}
+
+func (m *scopeMatcher) table(name string) bool {
+	return matchScope(name, m.includeTables, m.excludeTables)
+}
+
+func (m *scopeMatcher) column(name string) bool {
+	return matchScope(name, m.includeColumns, m.excludeColumns)
+}
+
+func matchScope(name string, include, exclude []*regexp.Regexp) bool {
+	if len(include) > 0 && !matchAny(name, include) {
+		return false
+	}
+	return !matchAny(name, exclude)
+}
+
+func matchAny(name string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveScope combines the scope stored on conn with the lists set in
+// override and, on MySQL, the connection's database. It returns nil when the
+// scan is not restricted at all.
+func effectiveScope(conn *domain.DatabaseConnection, override *domain.ScanScope) *domain.ScanScope {
+	var scope domain.ScanScope
+	if conn.ScanScope != nil {
+		scope = *conn.ScanScope
+	}
+	scope.Database = ""
+
+	if override != nil {
+		overrideList(&scope.IncludeSchemas, override.IncludeSchemas)
+		overrideList(&scope.ExcludeSchemas, override.ExcludeSchemas)
+		overrideList(&scope.IncludeTables, override.IncludeTables)
+		overrideList(&scope.ExcludeTables, override.ExcludeTables)
+		overrideList(&scope.IncludeColumns, override.IncludeColumns)
+		overrideList(&scope.ExcludeColumns, override.ExcludeColumns)
+	}
+
+	// PostgreSQL and SQLite connections are already bound to one database
+	if conn.Engine == domain.EngineMySQL {
+		scope.Database = conn.DatabaseName
+	}
+
+	if scopeIsEmpty(&scope) {
+		return nil
+	}
+	return &scope
+}
+
+// scopeOverridden reports whether override changes the scope the connection
+// is scanned with. A scan with a different scope does not cover what the
+// connection's other scans cover, so it is not taken as their latest.
+func scopeOverridden(conn *domain.DatabaseConnection, override *domain.ScanScope) bool {
+	if override == nil {
+		return false
+	}
+
+	scope, own := effectiveScope(conn, override), effectiveScope(conn, nil)
+	if scope == nil || own == nil {
+		return scope != own
+	}
+	return !slices.Equal(scope.IncludeSchemas, own.IncludeSchemas) || !slices.Equal(scope.ExcludeSchemas, own.ExcludeSchemas) ||
+		!slices.Equal(scope.IncludeTables, own.IncludeTables) || !slices.Equal(scope.ExcludeTables, own.ExcludeTables) ||
+		!slices.Equal(scope.IncludeColumns, own.IncludeColumns) || !slices.Equal(scope.ExcludeColumns, own.ExcludeColumns)
+}
+
+// overrideList replaces dest with list when list was given, so that an empty
+// list in a request clears the connection's list for that scan.
+func overrideList(dest *[]string, list []string) {
+	if list != nil {
+		*dest = list
+	}
+}
+
+func scopeIsEmpty(scope *domain.ScanScope) bool {
+	return scope.Database == "" &&
+		len(scope.IncludeSchemas) == 0 && len(scope.ExcludeSchemas) == 0 &&
+		len(scope.IncludeTables) == 0 && len(scope.ExcludeTables) == 0 &&
+		len(scope.IncludeColumns) == 0 && len(scope.ExcludeColumns) == 0
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"database-classifier/internal/domain"
+)
+
+func TestScopeOverridden(t *testing.T) {
+	conn := &domain.DatabaseConnection{
+		Engine:       domain.EngineMySQL,
+		DatabaseName: "shop",
+		ScanScope:    &domain.ScanScope{ExcludeTables: []string{"audit_*"}},
+	}
+	unscoped := &domain.DatabaseConnection{Engine: domain.EnginePostgres}
+
+	tests := []struct {
+		name     string
+		conn     *domain.DatabaseConnection
+		override *domain.ScanScope
+		want     bool
+	}{
+		{"no override", conn, nil, false},
+		{"empty override", conn, &domain.ScanScope{}, false},
+		{"same list", conn, &domain.ScanScope{ExcludeTables: []string{"audit_*"}}, false},
+		{"narrowed", conn, &domain.ScanScope{IncludeTables: []string{"customers"}}, true},
+		{"cleared list", conn, &domain.ScanScope{ExcludeTables: []string{}}, true},
+		{"empty list on an unscoped connection", unscoped, &domain.ScanScope{IncludeSchemas: []string{}}, false},
+		{"unscoped connection narrowed", unscoped, &domain.ScanScope{IncludeSchemas: []string{"public"}}, true},
+	}
+
+	for _, tt := range tests {
+		if got := scopeOverridden(tt.conn, tt.override); got != tt.want {
+			t.Errorf("%s: scopeOverridden = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,32 +15,56 @@ import (
 )
 
 type ScanService struct {
-	scanRepo            domain.ScanResultRepository
-	dbConnRepo          domain.DatabaseConnectionRepository
-	encryptor           *security.Encryptor
-	classificationSvc   domain.ClassificationService
+	scanRepo          domain.ScanResultRepository
+	jobRepo           domain.ScanJobRepository
+	dbConnRepo        domain.DatabaseConnectionRepository
+	encryptor         *security.Encryptor
+	classificationSvc domain.ClassificationService
+	riskPolicyRepo    domain.RiskPolicyRepository
+	queue             ScanQueueOptions
+
+	mu       sync.Mutex
+	running  map[uuid.UUID]context.CancelFunc
+	trackers map[uuid.UUID]*progressTracker
+	wake     chan struct{}
 }
 
 func NewScanService(
 	scanRepo domain.ScanResultRepository,
+	jobRepo domain.ScanJobRepository,
 	dbConnRepo domain.DatabaseConnectionRepository,
 	encryptor *security.Encryptor,
 	classificationSvc domain.ClassificationService,
+	riskPolicyRepo domain.RiskPolicyRepository,
+	queue ScanQueueOptions,
 ) *ScanService {
 	return &ScanService{
 		scanRepo:          scanRepo,
+		jobRepo:           jobRepo,
 		dbConnRepo:        dbConnRepo,
 		encryptor:         encryptor,
 		classificationSvc: classificationSvc,
+		riskPolicyRepo:    riskPolicyRepo,
+		queue:             queue,
+		running:           make(map[uuid.UUID]context.CancelFunc),
+		trackers:          make(map[uuid.UUID]*progressTracker),
+		wake:              make(chan struct{}, 1),
 	}
 }
 
-func (s *ScanService) StartScan(ctx context.Context, databaseID uuid.UUID) (uuid.UUID, error) {
+// StartScan queues a scan of a database. Lists set in req.Scope replace those
+// of the connection's scan scope for this scan only.
+func (s *ScanService) StartScan(ctx context.Context, databaseID uuid.UUID, req domain.StartScanRequest) (uuid.UUID, error) {
 	conn, err := s.dbConnRepo.GetByID(ctx, databaseID)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
+	scanScope := effectiveScope(conn, req.Scope)
+	if _, err := compileScope(scanScope); err != nil {
+		return uuid.Nil, err
+	}
+
 	scanID := uuid.New()
 	scanResult := &domain.ScanResult{
 		ID:         scanID,
@@ -47,124 +73,305 @@ func (s *ScanService) StartScan(ctx context.Context, databaseID uuid.UUID) (uuid
 		Summary: domain.ScanSummary{
 			InformationTypesCounts: make(map[domain.InformationType]int),
 		},
-		StartedAt: time.Now().UTC(),
+		StartedAt:       time.Now().UTC(),
+		Scope:           scanScope,
+		ScopeOverridden: scopeOverridden(conn, req.Scope),
+		Incremental:     req.Incremental,
 	}
 
 	if err := s.scanRepo.Create(ctx, scanResult); err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create scan result: %w", err)
 	}
 
-	go func() {
-		scanCtx := context.Background()
-		if err := s.performScan(scanCtx, scanResult, conn); err != nil {
-			s.scanRepo.UpdateStatus(scanCtx, scanResult.ID, domain.ScanStatusFailed, err.Error())
+	job := &domain.ScanJob{
+		ScanID:     scanID,
+		DatabaseID: databaseID,
+		Target:     scanTarget(conn),
+		EnqueuedAt: scanResult.StartedAt,
+	}
+	if err := s.jobRepo.Enqueue(ctx, job); err != nil {
+		if deleteErr := s.scanRepo.Delete(ctx, scanID); deleteErr != nil {
+			fmt.Printf("Warning: failed to remove unqueued scan %s: %v\n", scanID, deleteErr)
 		}
-	}()
+		return uuid.Nil, err
+	}
+
+	s.notifyWorkers()
 
 	return scanID, nil
 }
 
+// run executes a claimed scan in the calling goroutine with its own
+// cancellable context, registered so that CancelScan can stop it and
+// WatchScan can follow its progress.
+func (s *ScanService) run(scanResult *domain.ScanResult, conn *domain.DatabaseConnection) {
+	scanCtx, cancel := context.WithCancel(context.Background())
+	s.register(scanResult.ID, cancel)
+	defer s.unregister(scanResult.ID)
+	defer cancel()
+
+	tracker := s.trackProgress(scanResult.ID)
+	defer s.untrackProgress(scanResult.ID)
+
+	if err := s.scanRepo.Heartbeat(scanCtx, scanResult.ID); err != nil {
+		fmt.Printf("Warning: failed to record heartbeat for scan %s: %v\n", scanResult.ID, err)
+	}
+	go s.superviseScan(scanCtx, scanResult.ID, tracker, cancel)
+
+	if err := s.performScan(scanCtx, scanResult, conn, tracker); err != nil {
+		s.finishWithError(scanCtx, scanResult.ID, err)
+	}
+
+	s.finishProgress(tracker)
+}
+
+// finishWithError records a scan failure unless the scan was cancelled, in
+// which case the cancelled status written by CancelScan is left untouched.
+func (s *ScanService) finishWithError(scanCtx context.Context, scanID uuid.UUID, scanErr error) {
+	ctx := context.Background()
+	if scanCtx.Err() != nil {
+		if _, err := s.scanRepo.TransitionStatus(ctx, scanID, domain.ScanStatusCancelled, "Cancelled by user", domain.ScanStatusPending, domain.ScanStatusRunning); err != nil {
+			fmt.Printf("Warning: failed to mark scan %s as cancelled: %v\n", scanID, err)
+		}
+		return
+	}
+
+	if _, err := s.scanRepo.TransitionStatus(ctx, scanID, domain.ScanStatusFailed, scanErr.Error(), domain.ScanStatusPending, domain.ScanStatusRunning); err != nil {
+		fmt.Printf("Warning: failed to mark scan %s as failed: %v\n", scanID, err)
+	}
+}
+
+func (s *ScanService) register(scanID uuid.UUID, cancel context.CancelFunc) {
+	s.mu.Lock()
+	s.running[scanID] = cancel
+	s.mu.Unlock()
+}
+
+func (s *ScanService) unregister(scanID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.running, scanID)
+	s.mu.Unlock()
+}
+
+// cancelLocal stops the scan goroutine if it runs in this process.
+func (s *ScanService) cancelLocal(scanID uuid.UUID) {
+	s.mu.Lock()
+	cancel, ok := s.running[scanID]
+	s.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// superviseScan records a heartbeat and the progress of a scan while it runs
+// and cancels its context once the scan has been cancelled, possibly from
+// another replica.
+func (s *ScanService) superviseScan(ctx context.Context, scanID uuid.UUID, tracker *progressTracker, cancel context.CancelFunc) {
+	ticker := time.NewTicker(domain.ScanHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.scanRepo.Heartbeat(ctx, scanID); err != nil {
+				fmt.Printf("Warning: failed to record heartbeat for scan %s: %v\n", scanID, err)
+			}
+
+			if progress, changed := tracker.snapshot(); changed {
+				if err := s.scanRepo.UpdateProgress(ctx, scanID, progress); err != nil {
+					fmt.Printf("Warning: failed to record progress for scan %s: %v\n", scanID, err)
+				}
+			}
 
-func (s *ScanService) performScan(ctx context.Context, scanResult *domain.ScanResult, conn *domain.DatabaseConnection) error {
+			status, err := s.scanRepo.GetStatus(ctx, scanID)
+			if err != nil {
+				continue
+			}
+			if status == domain.ScanStatusCancelled {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (s *ScanService) performScan(ctx context.Context, scanResult *domain.ScanResult, conn *domain.DatabaseConnection, tracker *progressTracker) error {
 	startTime := time.Now()
 
-	if err := s.scanRepo.UpdateStatus(ctx, scanResult.ID, domain.ScanStatusRunning, ""); err != nil {
+	started, err := s.scanRepo.TransitionStatus(ctx, scanResult.ID, domain.ScanStatusRunning, "", domain.ScanStatusPending)
+	if err != nil {
 		return fmt.Errorf("failed to update scan status to running: %w", err)
 	}
+	if !started {
+		return nil
+	}
 
-	password, err := s.encryptor.Decrypt(conn.EncryptedPassword)
+	scope, err := compileScope(scanResult.Scope)
+	if err != nil {
+		return err
+	}
+
+	password, err := decryptPassword(s.encryptor, conn)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt password: %w", err)
 	}
 
-	inspector := database.NewMySQLInspector()
+	inspector, err := database.NewInspector(conn.Engine)
+	if err != nil {
+		return err
+	}
 	defer inspector.Close()
 
-	if err := inspector.Connect(conn.Host, conn.Port, conn.Username, password); err != nil {
-		return fmt.Errorf("failed to connect to MySQL: %w", err)
+	if err := inspector.Connect(ctx, conn.Host, conn.Port, conn.Username, password, connectionDatabase(conn)); err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", conn.Engine, err)
 	}
 
-	schemas, err := inspector.GetSchemas()
+	schemas, err := inspector.GetSchemas(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get schemas: %w", err)
 	}
 
 	var schemaResults []domain.SchemaResult
+	totalSchemas := 0
 	totalTables := 0
 	totalColumns := 0
 	classifiedColumns := 0
+	reusedTables := 0
 	infoTypeCounts := make(map[domain.InformationType]int)
 
+	concurrency := conn.ScanConcurrency
+	if concurrency <= 0 {
+		concurrency = s.queue.TableConcurrency
+	}
+
+	var errs *scanErrorLog
+	if s.queue.TolerateTableErrors {
+		errs = &scanErrorLog{}
+	}
+
+	var base *baseScan
+	if scanResult.Incremental {
+		if base = s.loadBaseScan(ctx, scanResult.DatabaseID); base != nil {
+			scanResult.BaseScanID = &base.id
+		}
+	}
+
+	// Every catalog in scope is read before classifying so that progress
+	// can report the total number of tables from the start
+	type schemaTables struct {
+		name   string
+		tables []*domain.TableInfo
+	}
+	var inScope []schemaTables
+
 	for _, schemaName := range schemas {
-		tables, err := inspector.GetTables(schemaName)
-		if err != nil {
-			return fmt.Errorf("failed to get tables for schema %s: %w", schemaName, err)
+		if !scope.schema(schemaName) {
+			continue
 		}
 
-		var tableResults []domain.TableResult
-		totalTables += len(tables)
+		tables, err := inspector.GetSchemaTables(ctx, schemaName)
+		if err != nil {
+			if errs == nil || ctx.Err() != nil || database.IsConnectionError(err) {
+				return fmt.Errorf("failed to get tables for schema %s: %w", schemaName, err)
+			}
 
-		for _, tableName := range tables {
-			tableInfo, err := inspector.GetTableInfo(schemaName, tableName)
+			var skipped bool
+			tables, skipped, err = readTablesOneByOne(ctx, inspector, scope, errs, schemaName)
 			if err != nil {
-				return fmt.Errorf("failed to get table info for %s.%s: %w", schemaName, tableName, err)
+				return err
+			}
+			if skipped {
+				continue
 			}
+		}
 
-			var columnResults []domain.ColumnResult
-			totalColumns += len(tableInfo.Columns)
+		var scoped []*domain.TableInfo
+		for _, tableInfo := range tables {
+			if !scope.table(tableInfo.TableName) {
+				continue
+			}
 
+			// Columns out of scope are neither sampled nor classified
+			columns := tableInfo.Columns[:0]
 			for _, colInfo := range tableInfo.Columns {
-				infoType, score, matched := s.classificationSvc.ClassifyColumn(colInfo.ColumnName)
-
-				columnResult := domain.ColumnResult{
-					ColumnName:      colInfo.ColumnName,
-					DataType:        colInfo.DataType,
-					InformationType: infoType,
-					ConfidenceScore: score,
-					MatchedPatterns: matched,
-					IsNullable:      colInfo.IsNullable,
-					DefaultValue:    colInfo.DefaultValue,
+				if scope.column(colInfo.ColumnName) {
+					columns = append(columns, colInfo)
 				}
+			}
+			tableInfo.Columns = columns
 
-				columnResults = append(columnResults, columnResult)
+			scoped = append(scoped, tableInfo)
+		}
 
-				if infoType != domain.InfoTypeNA {
+		inScope = append(inScope, schemaTables{name: schemaName, tables: scoped})
+		totalTables += len(scoped)
+	}
+
+	totalSchemas = len(inScope)
+	tracker.setTotals(totalSchemas, totalTables)
+
+	for _, schema := range inScope {
+		tableResults, err := s.classifyTables(ctx, inspector, tracker, errs, base, schema.name, schema.tables, conn.SampleSize, conn.PatternPacks, concurrency)
+		if err != nil {
+			return err
+		}
+
+		for _, tableResult := range tableResults {
+			if tableResult.Reused {
+				reusedTables++
+			}
+			totalColumns += len(tableResult.Columns)
+			for _, columnResult := range tableResult.Columns {
+				if columnResult.InformationType != domain.InfoTypeNA {
 					classifiedColumns++
-					infoTypeCounts[infoType]++
+					infoTypeCounts[columnResult.InformationType]++
 				}
 			}
-
-			tableResults = append(tableResults, domain.TableResult{
-				TableName: tableName,
-				Columns:   columnResults,
-			})
 		}
 
 		schemaResults = append(schemaResults, domain.SchemaResult{
-			SchemaName: schemaName,
+			SchemaName: schema.name,
 			Tables:     tableResults,
 		})
+		tracker.finishSchema()
 	}
 
-	riskLevel := s.calculateRiskLevel(infoTypeCounts, totalColumns)
+	scanErrors := errs.list()
+	skippedSchemas, skippedTables := countSkipped(scanErrors)
 
 	endTime := time.Now()
 	scanResult.CompletedAt = &endTime
 	scanResult.Status = domain.ScanStatusCompleted
+	if len(scanErrors) > 0 {
+		scanResult.Status = domain.ScanStatusCompletedWithErrors
+	}
 	scanResult.Schemas = schemaResults
+	scanResult.Errors = scanErrors
+	scanResult.Progress, _ = tracker.snapshot()
 	scanResult.Summary = domain.ScanSummary{
-		TotalSchemas:           len(schemas),
+		TotalSchemas:           totalSchemas,
 		TotalTables:            totalTables,
 		TotalColumns:           totalColumns,
 		ClassifiedColumns:      classifiedColumns,
 		InformationTypesCounts: infoTypeCounts,
-		RiskLevel:              riskLevel,
 		DurationMilliseconds:   endTime.Sub(startTime).Milliseconds(),
+		SkippedSchemas:         skippedSchemas,
+		SkippedTables:          skippedTables,
+		ReusedTables:           reusedTables,
 	}
+	assessRisk(scanResult, s.riskPolicy(ctx, conn), s.classificationSvc)
 
-	if err := s.scanRepo.Update(ctx, scanResult); err != nil {
+	updated, err := s.scanRepo.UpdateIfStatus(ctx, scanResult, domain.ScanStatusRunning)
+	if err != nil {
 		return fmt.Errorf("failed to update scan result: %w", err)
 	}
+	if !updated {
+		// The scan was cancelled while its result was being assembled.
+		return nil
+	}
 
 	if err := s.dbConnRepo.UpdateLastScannedAt(ctx, conn.ID, endTime); err != nil {
 		fmt.Printf("Warning: failed to update last scanned time: %v\n", err)
@@ -173,57 +380,198 @@ func (s *ScanService) performScan(ctx context.Context, scanResult *domain.ScanRe
 	return nil
 }
 
-func (s *ScanService) calculateRiskLevel(infoTypeCounts map[domain.InformationType]int, totalColumns int) domain.RiskLevel {
-	if totalColumns == 0 {
-		return domain.RiskLevelLow
+// classifyTables samples and classifies tables with up to concurrency workers
+// and returns their results in the order of tables. Workers share the
+// inspector, so concurrency also bounds the sampling queries run at once.
+// Tables unchanged since base are reused without touching the inspector.
+// Columns are matched against the core patterns and those of packs.
+func (s *ScanService) classifyTables(ctx context.Context, inspector domain.Inspector, tracker *progressTracker, errs *scanErrorLog, base *baseScan, schemaName string, tables []*domain.TableInfo, sampleSize int, packs []string, concurrency int) ([]domain.TableResult, error) {
+	results := make([]domain.TableResult, len(tables))
+	next := make(chan int)
+	classifierVersion := s.classificationSvc.ConfigVersion()
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(concurrency, 1), len(tables)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				tracker.startTable(schemaName, tables[i].TableName)
+				if result, ok := base.reuse(tables[i], sampleSize, packs, classifierVersion); ok {
+					results[i] = result
+				} else {
+					results[i] = s.classifyTable(ctx, inspector, errs, tables[i], sampleSize, packs, classifierVersion)
+				}
+				tracker.finishTable(schemaName, results[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range tables {
+		if errs.connectionLost() != nil {
+			break
+		}
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
 	}
+	close(next)
+	wg.Wait()
 
-	highRiskTypes := []domain.InformationType{
-		domain.InfoTypeCreditCardNumber,
-		domain.InfoTypeSSN,
-		domain.InfoTypePassportNumber,
-		domain.InfoTypeNationalID,
-		domain.InfoTypeBankAccount,
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	if err := errs.connectionLost(); err != nil {
+		return nil, fmt.Errorf("lost connection while sampling schema %s: %w", schemaName, err)
+	}
+
+	return results, nil
+}
+
+func (s *ScanService) classifyTable(ctx context.Context, inspector domain.Inspector, errs *scanErrorLog, tableInfo *domain.TableInfo, sampleSize int, packs []string, classifierVersion string) domain.TableResult {
+	samples := s.sampleTable(ctx, inspector, errs, tableInfo, sampleSize)
 
-	mediumRiskTypes := []domain.InformationType{
-		domain.InfoTypeEmailAddress,
-		domain.InfoTypePhoneNumber,
-		domain.InfoTypeDateOfBirth,
-		domain.InfoTypeDriverLicense,
-		domain.InfoTypeAccountNumber,
+	siblings := make([]string, len(tableInfo.Columns))
+	for i, colInfo := range tableInfo.Columns {
+		siblings[i] = colInfo.ColumnName
 	}
 
-	highRiskCount := 0
-	mediumRiskCount := 0
+	var columnResults []domain.ColumnResult
+	for _, colInfo := range tableInfo.Columns {
+		infoType, score, matched, contextRules := s.classificationSvc.ClassifyColumn(domain.ColumnContext{
+			SchemaName:     tableInfo.SchemaName,
+			TableName:      tableInfo.TableName,
+			ColumnName:     colInfo.ColumnName,
+			DataType:       colInfo.DataType,
+			ColumnKey:      colInfo.ColumnKey,
+			MaxLength:      colInfo.MaxLength,
+			Comment:        colInfo.Comment,
+			TableComment:   tableInfo.Comment,
+			SiblingColumns: siblings,
+			Packs:          packs,
+		})
 
-	for infoType, count := range infoTypeCounts {
-		for _, hrType := range highRiskTypes {
-			if infoType == hrType {
-				highRiskCount += count
-				break
-			}
+		columnResult := domain.ColumnResult{
+			ColumnName:      colInfo.ColumnName,
+			DataType:        colInfo.DataType,
+			InformationType: infoType,
+			ConfidenceScore: score,
+			MatchedPatterns: matched,
+			IsNullable:      colInfo.IsNullable,
+			DefaultValue:    colInfo.DefaultValue,
+			ContextRules:    contextRules,
+			MaxLength:       colInfo.MaxLength,
+			Comment:         colInfo.Comment,
+		}
+
+		if values, ok := samples[colInfo.ColumnName]; ok {
+			s.applyValueClassification(&columnResult, values)
 		}
-		for _, mrType := range mediumRiskTypes {
-			if infoType == mrType {
-				mediumRiskCount += count
-				break
+
+		columnResults = append(columnResults, columnResult)
+	}
+
+	return domain.TableResult{
+		TableName:   tableInfo.TableName,
+		Columns:     columnResults,
+		Fingerprint: tableFingerprint(tableInfo, sampleSize, packs, classifierVersion),
+		DataVersion: tableInfo.DataVersion,
+	}
+}
+
+// sampleTable pulls value samples for the sampleable columns of a table. Sampling
+// is best effort: a failure only drops the value evidence for that table, and
+// is recorded in errs when the scan tolerates table errors.
+func (s *ScanService) sampleTable(ctx context.Context, inspector domain.Inspector, errs *scanErrorLog, tableInfo *domain.TableInfo, sampleSize int) map[string][]string {
+	if sampleSize <= 0 {
+		return nil
+	}
+
+	var columns []string
+	for _, colInfo := range tableInfo.Columns {
+		if isSampleableDataType(colInfo.DataType) {
+			columns = append(columns, colInfo.ColumnName)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	samples, err := inspector.SampleTableValues(ctx, tableInfo.SchemaName, tableInfo.TableName, columns, sampleSize)
+	if err != nil {
+		if !errs.tolerate(ctx, tableInfo.SchemaName, tableInfo.TableName, domain.ScanPhaseSample, err) {
+			fmt.Printf("Warning: failed to sample %s.%s: %v\n", tableInfo.SchemaName, tableInfo.TableName, err)
+		}
+		return nil
+	}
+
+	return samples
+}
+
+// applyValueClassification merges the value-level detection for the sampled
+// values into the name-based classification already held by columnResult.
+func (s *ScanService) applyValueClassification(columnResult *domain.ColumnResult, values []string) {
+	columnResult.SampleSize = len(values)
+
+	// A name match whose pattern references a validator only keeps the share
+	// of its confidence backed by sampled values that pass the validator.
+	if columnResult.InformationType != domain.InfoTypeNA && len(columnResult.MatchedPatterns) > 0 {
+		passed, total, ok := s.classificationSvc.ValidateValues(columnResult.MatchedPatterns[0], values)
+		if ok && total > 0 {
+			columnResult.ConfidenceScore *= float64(passed) / float64(total)
+			if passed == 0 {
+				columnResult.InformationType = domain.InfoTypeNA
 			}
 		}
 	}
 
-	totalSensitiveColumns := highRiskCount + mediumRiskCount
-	riskPercentage := float64(totalSensitiveColumns) / float64(totalColumns) * 100
+	valueType, valueScore, matchedSamples, detector := s.classificationSvc.ClassifyValues(columnResult.DataType, values)
+	if valueType == domain.InfoTypeNA {
+		return
+	}
+
+	columnResult.MatchedSamples = matchedSamples
+	columnResult.MatchedPatterns = append(columnResult.MatchedPatterns, "value:"+detector)
 
-	if highRiskCount > 0 && riskPercentage > 20 {
-		return domain.RiskLevelCritical
-	} else if highRiskCount > 0 || riskPercentage > 15 {
-		return domain.RiskLevelHigh
-	} else if mediumRiskCount > 0 || riskPercentage > 5 {
-		return domain.RiskLevelMedium
+	switch {
+	case columnResult.InformationType == valueType:
+		// Independent evidence for the same type reinforces the name score.
+		columnResult.ConfidenceScore += valueScore * (1 - columnResult.ConfidenceScore)
+	case valueScore > columnResult.ConfidenceScore:
+		columnResult.InformationType = valueType
+		columnResult.ConfidenceScore = valueScore
 	}
+}
 
-	return domain.RiskLevelLow
+func isSampleableDataType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext",
+		"int", "bigint", "decimal":
+		return true
+	// PostgreSQL names, as reported by format_type
+	case "character", "character varying", "citext",
+		"integer", "numeric", "inet", "macaddr":
+		return true
+	}
+	return false
+}
+
+// riskPolicy returns the risk policy assigned to a connection, or nil to
+// score its scans with the built-in calculation.
+func (s *ScanService) riskPolicy(ctx context.Context, conn *domain.DatabaseConnection) *domain.RiskPolicy {
+	if conn.RiskPolicyID == nil {
+		return nil
+	}
+
+	policy, err := s.riskPolicyRepo.GetByID(ctx, *conn.RiskPolicyID)
+	if err != nil {
+		fmt.Printf("Warning: scoring scan of %s without its risk policy: %v\n", conn.ID, err)
+		return nil
+	}
+	return policy
 }
 
 func (s *ScanService) GetScanResult(ctx context.Context, scanID uuid.UUID) (*domain.ScanResult, error) {
@@ -232,6 +580,17 @@ func (s *ScanService) GetScanResult(ctx context.Context, scanID uuid.UUID) (*dom
 		return nil, fmt.Errorf("failed to get scan result: %w", err)
 	}
 
+	if result.Status == domain.ScanStatusPending {
+		position, err := s.jobRepo.GetQueuePosition(ctx, scanID)
+		if err != nil {
+			return nil, err
+		}
+		if position > 0 {
+			result.QueuePosition = &position
+		}
+	}
+	s.backfillRisk(ctx, result)
+
 	return result, nil
 }
 
@@ -248,15 +607,55 @@ func (s *ScanService) GetScanHistory(ctx context.Context, databaseID uuid.UUID,
 	return results, nil
 }
 
-func (s *ScanService) GetLatestClassification(ctx context.Context, databaseID uuid.UUID) (*domain.ScanResult, error) {
+// GetLatestClassification returns the latest completed scan of a database,
+// with its schemas and tables sorted by sortBy.
+func (s *ScanService) GetLatestClassification(ctx context.Context, databaseID uuid.UUID, sortBy domain.ClassificationSort) (*domain.ScanResult, error) {
 	result, err := s.scanRepo.GetLatestByDatabaseID(ctx, databaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest classification: %w", err)
 	}
 
+	s.backfillRisk(ctx, result)
+	if sortBy == domain.ClassificationSortRisk {
+		sortByRisk(result)
+	}
+
 	return result, nil
 }
 
+// backfillRisk computes the schema and table risk of a completed scan stored
+// before they were recorded, with the policy its summary was scored with.
+// The result is not stored.
+func (s *ScanService) backfillRisk(ctx context.Context, result *domain.ScanResult) {
+	if result.Status != domain.ScanStatusCompleted && result.Status != domain.ScanStatusCompletedWithErrors {
+		return
+	}
+	missing := false
+	for _, schema := range result.Schemas {
+		if schema.RiskLevel == "" {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return
+	}
+
+	var policy *domain.RiskPolicy
+	if result.Summary.RiskPolicyID != nil {
+		var err error
+		policy, err = s.riskPolicyRepo.GetByID(ctx, *result.Summary.RiskPolicyID)
+		if err != nil {
+			// The policy was deleted; leave the scan as stored
+			return
+		}
+	}
+
+	for i := range result.Schemas {
+		assessSchemaRisk(&result.Schemas[i], policy, s.classificationSvc)
+	}
+}
+
 func (s *ScanService) CancelScan(ctx context.Context, scanID uuid.UUID) error {
 	scanResult, err := s.scanRepo.GetByID(ctx, scanID)
 	if err != nil {
@@ -267,9 +666,21 @@ func (s *ScanService) CancelScan(ctx context.Context, scanID uuid.UUID) error {
 		return fmt.Errorf("scan cannot be cancelled, current status: %s", scanResult.Status)
 	}
 
-	if err := s.scanRepo.UpdateStatus(ctx, scanID, domain.ScanStatusCancelled, "Cancelled by user"); err != nil {
+	cancelled, err := s.scanRepo.TransitionStatus(ctx, scanID, domain.ScanStatusCancelled, "Cancelled by user", domain.ScanStatusPending, domain.ScanStatusRunning)
+	if err != nil {
 		return fmt.Errorf("failed to cancel scan: %w", err)
 	}
+	if !cancelled {
+		return fmt.Errorf("scan cannot be cancelled, it finished before the cancellation was applied")
+	}
+
+	if scanResult.Status == domain.ScanStatusPending {
+		if err := s.jobRepo.Delete(ctx, scanID); err != nil {
+			return err
+		}
+	}
+
+	s.cancelLocal(scanID)
 
 	return nil
 }
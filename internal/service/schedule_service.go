@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"database-classifier/internal/domain"
+)
+
+const (
+	schedulerLeaseName = "scan-scheduler"
+	schedulerBatchSize = 100
+)
+
+type ScheduleService struct {
+	scheduleRepo domain.ScanScheduleRepository
+	leaseRepo    domain.LeaderLeaseRepository
+	dbConnRepo   domain.DatabaseConnectionRepository
+	scanSvc      domain.ScanService
+	instanceID   string
+}
+
+func NewScheduleService(
+	scheduleRepo domain.ScanScheduleRepository,
+	leaseRepo domain.LeaderLeaseRepository,
+	dbConnRepo domain.DatabaseConnectionRepository,
+	scanSvc domain.ScanService,
+	instanceID string,
+) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo: scheduleRepo,
+		leaseRepo:    leaseRepo,
+		dbConnRepo:   dbConnRepo,
+		scanSvc:      scanSvc,
+		instanceID:   instanceID,
+	}
+}
+
+func (s *ScheduleService) CreateSchedule(ctx context.Context, databaseID uuid.UUID, req *domain.CreateScheduleRequest) (*domain.ScanSchedule, error) {
+	if _, err := s.dbConnRepo.GetByID(ctx, databaseID); err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	now := time.Now().UTC()
+	schedule := &domain.ScanSchedule{
+		ID:              uuid.New(),
+		DatabaseID:      databaseID,
+		CronExpression:  req.CronExpression,
+		IntervalSeconds: req.IntervalSeconds,
+		Timezone:        timezone,
+		Incremental:     req.Incremental,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	next, err := nextRun(schedule, now)
+	if err != nil {
+		return nil, err
+	}
+	schedule.NextRunAt = next
+
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) GetSchedules(ctx context.Context, databaseID uuid.UUID) ([]*domain.ScanSchedule, error) {
+	return s.scheduleRepo.GetByDatabaseID(ctx, databaseID)
+}
+
+func (s *ScheduleService) GetAllSchedules(ctx context.Context) ([]*domain.ScanSchedule, error) {
+	return s.scheduleRepo.GetAll(ctx)
+}
+
+func (s *ScheduleService) PauseSchedule(ctx context.Context, id uuid.UUID) error {
+	schedule, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	schedule.IsPaused = true
+	schedule.UpdatedAt = time.Now().UTC()
+	return s.scheduleRepo.Update(ctx, schedule)
+}
+
+// ResumeSchedule unpauses a schedule. Runs missed while paused are skipped;
+// the next run is computed from now.
+func (s *ScheduleService) ResumeSchedule(ctx context.Context, id uuid.UUID) error {
+	schedule, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	next, err := nextRun(schedule, now)
+	if err != nil {
+		return err
+	}
+
+	schedule.IsPaused = false
+	schedule.NextRunAt = next
+	schedule.UpdatedAt = now
+	return s.scheduleRepo.Update(ctx, schedule)
+}
+
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	return s.scheduleRepo.Delete(ctx, id)
+}
+
+// Run fires due schedules every pollInterval while this instance holds the
+// scheduler lease, until ctx is done. Only the lease holder fires schedules,
+// so each run is enqueued by a single replica.
+func (s *ScheduleService) Run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	// The lease outlives a couple of missed ticks so a slow tick does not hand
+	// leadership over, while a dead leader is replaced within a few intervals.
+	leaseTTL := 3 * pollInterval
+
+	for {
+		leader, err := s.leaseRepo.TryAcquire(ctx, schedulerLeaseName, s.instanceID, leaseTTL)
+		if err != nil {
+			fmt.Printf("Warning: failed to acquire scheduler lease: %v\n", err)
+		} else if leader {
+			s.fireDue(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			if leader {
+				if err := s.leaseRepo.Release(context.Background(), schedulerLeaseName, s.instanceID); err != nil {
+					fmt.Printf("Warning: failed to release scheduler lease: %v\n", err)
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ScheduleService) fireDue(ctx context.Context) {
+	now := time.Now().UTC()
+	schedules, err := s.scheduleRepo.GetDue(ctx, now, schedulerBatchSize)
+	if err != nil {
+		fmt.Printf("Warning: failed to load due schedules: %v\n", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		next, err := nextRun(schedule, now)
+		if err != nil {
+			fmt.Printf("Warning: schedule %s has an invalid definition: %v\n", schedule.ID, err)
+			continue
+		}
+
+		fired, err := s.scheduleRepo.MarkFired(ctx, schedule.ID, schedule.NextRunAt, next, now)
+		if err != nil {
+			fmt.Printf("Warning: failed to claim schedule %s: %v\n", schedule.ID, err)
+			continue
+		}
+		if !fired {
+			continue
+		}
+
+		scanID, err := s.scanSvc.StartScan(ctx, schedule.DatabaseID, domain.StartScanRequest{Incremental: schedule.Incremental})
+		if errors.Is(err, domain.ErrScanInProgress) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Warning: scheduled scan for database %s failed to start: %v\n", schedule.DatabaseID, err)
+			continue
+		}
+
+		if err := s.scheduleRepo.SetLastScan(ctx, schedule.ID, scanID); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+}
+
+// nextRun returns the first run of schedule strictly after after.
+func nextRun(schedule *domain.ScanSchedule, after time.Time) (time.Time, error) {
+	hasCron := schedule.CronExpression != ""
+	hasInterval := schedule.IntervalSeconds > 0
+	if hasCron == hasInterval {
+		return time.Time{}, fmt.Errorf("exactly one of cron_expression or interval_seconds is required")
+	}
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone %q: %w", schedule.Timezone, err)
+	}
+
+	if hasInterval {
+		return after.Add(time.Duration(schedule.IntervalSeconds) * time.Second).UTC(), nil
+	}
+
+	parsed, err := cron.ParseStandard(schedule.CronExpression)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression %q: %w", schedule.CronExpression, err)
+	}
+
+	return parsed.Next(after.In(location)).UTC(), nil
+}
@@ -1,4 +1,4 @@
-package classifier //nolint:stylecheck	
+package classifier //nolint:stylecheck
 
 import (
 	"fmt"
@@ -14,21 +14,49 @@ type Pattern struct {
 	Pattern         string                 `json:"pattern"`
 	Description     string                 `json:"description"`
 	Priority        int                    `json:"priority"`
-	regex           *regexp.Regexp
+	Validator       string                 `json:"validator,omitempty"`
+	Target          domain.PatternTarget   `json:"target,omitempty"`
+	MatchTokens     bool                   `json:"match_tokens,omitempty"`
+	Pack            string                 `json:"pack,omitempty"`
+	TablePattern    string                 `json:"table_pattern,omitempty"`
+	CoColumns       []string               `json:"co_columns,omitempty"`
+	ContextBoost    float64                `json:"context_boost,omitempty"`
+	// AllowedDataTypes and DisallowedDataTypes are lower-cased base types,
+	// e.g. varchar rather than VARCHAR(255).
+	AllowedDataTypes    []string `json:"allowed_data_types,omitempty"`
+	DisallowedDataTypes []string `json:"disallowed_data_types,omitempty"`
+	MinLength           *int     `json:"min_length,omitempty"`
+	MaxLength           *int     `json:"max_length,omitempty"`
+	regex               *regexp.Regexp
+	validate            Validator
+	tableRegex          *regexp.Regexp
+	coColumnRegexes     []*regexp.Regexp
 }
 
+// CommentMatchPrefix marks the entries of MatchResult.MatchedPatterns for
+// patterns that matched the column comment rather than its name.
+const CommentMatchPrefix = "comment:"
+
 type Classifier struct {
-	patterns []Pattern
+	patterns      []Pattern
+	detectors     []ValueDetector
+	abbreviations map[string][]string
 }
 
 type MatchResult struct {
 	InformationType domain.InformationType
 	ConfidenceScore float64
 	MatchedPatterns []string
+	ContextRules    []domain.ContextRuleMatch
 }
 
 func NewClassifier(patterns []*domain.ClassificationPattern) (*Classifier, error) {
-	c := &Classifier{}
+	detectors, err := DefaultValueDetectors()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Classifier{detectors: detectors}
 	if err := c.SetPatterns(patterns); err != nil {
 		return nil, err
 	}
@@ -38,17 +66,27 @@ func NewClassifier(patterns []*domain.ClassificationPattern) (*Classifier, error
 func (c *Classifier) SetPatterns(patterns []*domain.ClassificationPattern) error {
 	compiled := make([]Pattern, 0, len(patterns))
 	for _, p := range patterns {
-		regex, err := regexp.Compile(p.Pattern)
-		if err != nil {
-			return fmt.Errorf("failed to compile regex pattern '%s': %w", p.Pattern, err)
+		pattern := Pattern{
+			InformationType:     p.InformationType,
+			Pattern:             p.Pattern,
+			Description:         p.Description,
+			Priority:            p.Priority,
+			Validator:           p.Validator,
+			Target:              p.Target,
+			MatchTokens:         p.MatchTokens,
+			Pack:                p.Pack,
+			TablePattern:        p.TablePattern,
+			CoColumns:           p.CoColumns,
+			ContextBoost:        p.ContextBoost,
+			AllowedDataTypes:    p.AllowedDataTypes,
+			DisallowedDataTypes: p.DisallowedDataTypes,
+			MinLength:           p.MinLength,
+			MaxLength:           p.MaxLength,
 		}
-		compiled = append(compiled, Pattern{
-			InformationType: p.InformationType,
-			Pattern:         p.Pattern,
-			Description:     p.Description,
-			Priority:        p.Priority,
-			regex:           regex,
-		})
+		if err := pattern.compile(); err != nil {
+			return err
+		}
+		compiled = append(compiled, pattern)
 	}
 
 	sort.Slice(compiled, func(i, j int) bool {
@@ -59,33 +97,165 @@ func (c *Classifier) SetPatterns(patterns []*domain.ClassificationPattern) error
 	return nil
 }
 
-func (c *Classifier) ClassifyColumn(columnName string) MatchResult {
-	if columnName == "" {
-		return MatchResult{
-			InformationType: domain.InfoTypeNA,
-			ConfidenceScore: 0.0,
-			MatchedPatterns: []string{},
+// compile prepares the regular expressions and validator of a pattern. The
+// expressions are compiled without accents, as the texts they are matched
+// against are.
+func (p *Pattern) compile() error {
+	regex, err := regexp.Compile(FoldAccents(p.Pattern))
+	if err != nil {
+		return fmt.Errorf("failed to compile regex pattern '%s': %w", p.Pattern, err)
+	}
+	p.regex = regex
+
+	validate, err := resolveValidator(p.Validator)
+	if err != nil {
+		return err
+	}
+	p.validate = validate
+
+	if err := ValidateTarget(p.Target); err != nil {
+		return err
+	}
+	if err := ValidatePackName(p.Pack); err != nil {
+		return err
+	}
+
+	p.tableRegex = nil
+	if p.TablePattern != "" {
+		if p.tableRegex, err = regexp.Compile(FoldAccents(p.TablePattern)); err != nil {
+			return fmt.Errorf("failed to compile table pattern '%s': %w", p.TablePattern, err)
 		}
 	}
 
-	var matches []struct {
-		pattern Pattern
-		score   float64
+	p.AllowedDataTypes = normalizeDataTypes(p.AllowedDataTypes)
+	p.DisallowedDataTypes = normalizeDataTypes(p.DisallowedDataTypes)
+
+	p.coColumnRegexes = nil
+	for _, coColumn := range p.CoColumns {
+		regex, err := regexp.Compile(FoldAccents(coColumn))
+		if err != nil {
+			return fmt.Errorf("failed to compile co-column pattern '%s': %w", coColumn, err)
+		}
+		p.coColumnRegexes = append(p.coColumnRegexes, regex)
 	}
 
-	cleanName := strings.ToLower(strings.TrimSpace(columnName))
+	return nil
+}
 
-	for _, pattern := range c.patterns {
-		if pattern.regex.MatchString(cleanName) {
-			score := c.calculateConfidenceScore(cleanName, pattern)
-			matches = append(matches, struct {
-				pattern Pattern
-				score   float64
-			}{pattern, score})
+// ValidateContext checks that the context conditions of a pattern compile.
+func ValidateContext(tablePattern string, coColumns []string) error {
+	p := Pattern{TablePattern: tablePattern, CoColumns: coColumns}
+	return p.compile()
+}
+
+// ValidateTarget checks that target names a text a pattern can match.
+func ValidateTarget(target domain.PatternTarget) error {
+	switch target {
+	case "", domain.PatternTargetName, domain.PatternTargetComment, domain.PatternTargetAny:
+		return nil
+	default:
+		return fmt.Errorf("unknown pattern target '%s'", target)
+	}
+}
+
+// packEnabled reports whether the patterns of pack apply given the packs
+// enabled for a column. Core patterns always apply, and enabling a country
+// pack such as pt-br also enables its locale pack pt.
+func packEnabled(enabled []string, pack string) bool {
+	if pack == "" {
+		return true
+	}
+	for _, e := range enabled {
+		if e == pack || strings.HasPrefix(e, pack+"-") {
+			return true
 		}
 	}
+	return false
+}
 
-	if len(matches) == 0 {
+var packNameRegex = regexp.MustCompile(`^[a-z]{2}(-[a-z]{2})?$`)
+
+// ValidatePackName checks that name is empty, for core patterns, or names a
+// locale pack such as es or a country pack such as pt-br.
+func ValidatePackName(name string) error {
+	if name != "" && !packNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid pattern pack '%s'", name)
+	}
+	return nil
+}
+
+// patternSource is a text of a column a pattern matched, with the entry
+// recorded in MatchedPatterns and the penalty applied to its score.
+type patternSource struct {
+	matched string
+	text    string
+	penalty float64
+}
+
+// match returns the texts of a column the pattern matches. A pattern that
+// matches tokens and misses the name as written is tried on its normalized
+// tokens.
+func (p *Pattern) match(name string, tokens []string, comment string) []patternSource {
+	var sources []patternSource
+	if p.Target != domain.PatternTargetComment {
+		if p.regex.MatchString(name) {
+			sources = append(sources, patternSource{matched: p.Pattern, text: name})
+		} else if p.MatchTokens {
+			if text, uncovered, ok := p.matchTokens(tokens); ok {
+				sources = append(sources, patternSource{
+					matched: TokenMatchPrefix + p.Pattern,
+					text:    text,
+					penalty: float64(uncovered) * uncoveredTokenPenalty,
+				})
+			}
+		}
+	}
+	if (p.Target == domain.PatternTargetComment || p.Target == domain.PatternTargetAny) && comment != "" && p.regex.MatchString(comment) {
+		sources = append(sources, patternSource{matched: CommentMatchPrefix + p.Pattern, text: comment})
+	}
+	return sources
+}
+
+func (p *Pattern) hasContext() bool {
+	return p.tableRegex != nil || len(p.coColumnRegexes) > 0
+}
+
+// matchContext reports whether the table and co-column conditions of the
+// pattern hold for a column of tableName, returning the siblings that
+// satisfied them. The table condition also holds when it matches the table
+// comment.
+func (p *Pattern) matchContext(tableName, tableComment string, siblings []string) ([]string, bool) {
+	if p.tableRegex != nil && !p.tableRegex.MatchString(tableName) &&
+		(tableComment == "" || !p.tableRegex.MatchString(tableComment)) {
+		return nil, false
+	}
+
+	var matched []string
+	for _, regex := range p.coColumnRegexes {
+		found := false
+		for _, sibling := range siblings {
+			if regex.MatchString(sibling) {
+				matched = append(matched, sibling)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return matched, true
+}
+
+// ClassifyColumn matches the column name, its normalized tokens for patterns
+// that match tokens, and the column comment for patterns that target it,
+// against every pattern of the core set and of the packs enabled for column.
+// Names and comments are compared lower-cased and without accents. Patterns
+// with context conditions are skipped or boosted depending on the table name
+// and sibling columns of column.
+func (c *Classifier) ClassifyColumn(column domain.ColumnContext) MatchResult {
+	if column.ColumnName == "" {
 		return MatchResult{
 			InformationType: domain.InfoTypeNA,
 			ConfidenceScore: 0.0,
@@ -93,46 +263,142 @@ func (c *Classifier) ClassifyColumn(columnName string) MatchResult {
 		}
 	}
 
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].score > matches[j].score
-	})
+	matches, contextRules := c.matchColumn(column)
+
+	if len(matches) == 0 {
+		return MatchResult{
+			InformationType: domain.InfoTypeNA,
+			ConfidenceScore: 0.0,
+			MatchedPatterns: []string{},
+		}
+	}
 
 	bestMatch := matches[0]
 	matchedPatterns := make([]string, len(matches))
 	for i, match := range matches {
-		matchedPatterns[i] = match.pattern.Pattern
+		matchedPatterns[i] = match.source
 	}
 
 	return MatchResult{
 		InformationType: bestMatch.pattern.InformationType,
 		ConfidenceScore: bestMatch.score,
 		MatchedPatterns: matchedPatterns,
+		ContextRules:    contextRules,
 	}
 }
 
-func (c *Classifier) calculateConfidenceScore(columnName string, pattern Pattern) float64 {
-	baseScore := float64(pattern.Priority) / 100.0
+// columnMatch is a pattern that matched a column, with the text it matched
+// and how its score was made up.
+type columnMatch struct {
+	pattern   Pattern
+	source    string
+	text      string
+	score     float64
+	breakdown domain.ConfidenceBreakdown
+}
+
+// matchColumn returns every pattern that matches column, best first, along
+// with the context conditions that held.
+func (c *Classifier) matchColumn(column domain.ColumnContext) ([]columnMatch, []domain.ContextRuleMatch) {
+	var matches []columnMatch
+	var contextRules []domain.ContextRuleMatch
+
+	cleanName := normalizeText(column.ColumnName)
+	tokens := c.NormalizeName(column.ColumnName)
+	comment := normalizeText(column.Comment)
+	tableName := normalizeText(column.TableName)
+	tableComment := normalizeText(column.TableComment)
+	siblings := make([]string, 0, len(column.SiblingColumns))
+	for _, sibling := range column.SiblingColumns {
+		if sibling = normalizeText(sibling); sibling != cleanName {
+			siblings = append(siblings, sibling)
+		}
+	}
+
+	for _, pattern := range c.patterns {
+		if !packEnabled(column.Packs, pattern.Pack) {
+			continue
+		}
+
+		matchedSources := pattern.match(cleanName, tokens, comment)
+		if len(matchedSources) == 0 {
+			continue
+		}
+
+		boost := 0.0
+		if pattern.hasContext() {
+			coColumns, ok := pattern.matchContext(tableName, tableComment, siblings)
+			if !ok && pattern.ContextBoost == 0 {
+				continue
+			}
+			if ok {
+				boost = pattern.ContextBoost
+				contextRules = append(contextRules, domain.ContextRuleMatch{
+					Pattern:      pattern.Pattern,
+					TablePattern: pattern.TablePattern,
+					CoColumns:    coColumns,
+					Boost:        pattern.ContextBoost,
+				})
+			}
+		}
+
+		for _, source := range matchedSources {
+			score, breakdown := c.calculateConfidenceScore(source.text, column, pattern)
+			score = min(max(score-source.penalty+boost, 0), 1)
+			breakdown.TokenPenalty = source.penalty
+			breakdown.ContextBoost = boost
+			matches = append(matches, columnMatch{
+				pattern:   pattern,
+				source:    source.matched,
+				text:      source.text,
+				score:     score,
+				breakdown: breakdown,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	return matches, contextRules
+}
+
+// Adjustments of the confidence score for a column's data type and length
+// against the constraints of the pattern it matched.
+const (
+	dataTypeMatchBoost      = 0.05
+	dataTypeMismatchPenalty = 0.4
+	lengthMismatchPenalty   = 0.3
+)
+
+// calculateConfidenceScore scores a pattern match on a column name and
+// returns the score along with the terms it was made of.
+func (c *Classifier) calculateConfidenceScore(columnName string, column domain.ColumnContext, pattern Pattern) (float64, domain.ConfidenceBreakdown) {
+	breakdown := domain.ConfidenceBreakdown{
+		BasePriority: float64(pattern.Priority) / 100.0,
+	}
 
-	exactMatch := 0.0
 	if pattern.regex.MatchString(columnName) {
 		match := pattern.regex.FindString(columnName)
 		if match == columnName {
-			exactMatch = 0.2
+			breakdown.ExactMatchBonus = 0.2
 		} else {
-			exactMatch = 0.1
+			breakdown.ExactMatchBonus = 0.1
 		}
 	}
 
-	commonWordsPenalty := 0.0
 	commonWords := []string{"id", "name", "number", "date", "time", "status", "type"}
 	for _, word := range commonWords {
 		if strings.Contains(columnName, word) && len(columnName) < 10 {
-			commonWordsPenalty = 0.1
+			breakdown.CommonWordPenalty = 0.1
 			break
 		}
 	}
 
-	finalScore := baseScore + exactMatch - commonWordsPenalty
+	breakdown.TypeAdjustment = typeAdjustment(column, pattern)
+
+	finalScore := breakdown.BasePriority + breakdown.ExactMatchBonus - breakdown.CommonWordPenalty + breakdown.TypeAdjustment
 
 	if finalScore > 1.0 {
 		finalScore = 1.0
@@ -141,15 +407,82 @@ func (c *Classifier) calculateConfidenceScore(columnName string, pattern Pattern
 		finalScore = 0.0
 	}
 
-	return finalScore
+	return finalScore, breakdown
+}
+
+// typeAdjustment boosts a column whose data type the pattern allows and
+// penalizes one whose type or declared length the pattern rules out. Columns
+// whose type or length is unknown are neither boosted nor penalized.
+func typeAdjustment(column domain.ColumnContext, pattern Pattern) float64 {
+	adjustment := 0.0
+
+	if dataType := baseDataType(column.DataType); dataType != "" {
+		switch {
+		case containsString(pattern.DisallowedDataTypes, dataType):
+			adjustment -= dataTypeMismatchPenalty
+		case len(pattern.AllowedDataTypes) == 0:
+		case containsString(pattern.AllowedDataTypes, dataType):
+			adjustment += dataTypeMatchBoost
+		default:
+			adjustment -= dataTypeMismatchPenalty
+		}
+	}
+
+	if column.MaxLength != nil {
+		length := *column.MaxLength
+		if (pattern.MinLength != nil && length < int64(*pattern.MinLength)) ||
+			(pattern.MaxLength != nil && length > int64(*pattern.MaxLength)) {
+			adjustment -= lengthMismatchPenalty
+		}
+	}
+
+	return adjustment
+}
+
+// ValidateTypeConstraints checks that the length range of a pattern is not
+// empty.
+func ValidateTypeConstraints(minLength, maxLength *int) error {
+	if minLength != nil && maxLength != nil && *minLength > *maxLength {
+		return fmt.Errorf("min_length %d is greater than max_length %d", *minLength, *maxLength)
+	}
+	return nil
+}
+
+// baseDataType lower-cases a data type and strips its length or precision,
+// so that VARCHAR(255) compares equal to varchar.
+func baseDataType(dataType string) string {
+	if i := strings.IndexByte(dataType, '('); i >= 0 {
+		dataType = dataType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(dataType))
+}
+
+func normalizeDataTypes(dataTypes []string) []string {
+	if len(dataTypes) == 0 {
+		return nil
+	}
+	normalized := make([]string, 0, len(dataTypes))
+	for _, dataType := range dataTypes {
+		if dataType = baseDataType(dataType); dataType != "" {
+			normalized = append(normalized, dataType)
+		}
+	}
+	return normalized
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *Classifier) AddPattern(pattern Pattern) error {
-	regex, err := regexp.Compile(pattern.Pattern)
-	if err != nil {
-		return fmt.Errorf("failed to compile regex pattern '%s': %w", pattern.Pattern, err)
+	if err := pattern.compile(); err != nil {
+		return err
 	}
-	pattern.regex = regex
 
 	c.patterns = append(c.patterns, pattern)
 
@@ -160,6 +493,17 @@ func (c *Classifier) AddPattern(pattern Pattern) error {
 	return nil
 }
 
+func resolveValidator(name string) (Validator, error) {
+	if name == "" {
+		return nil, nil
+	}
+	validate, ok := LookupValidator(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown validator '%s'", name)
+	}
+	return validate, nil
+}
+
 func (c *Classifier) GetPatterns() []Pattern {
 	return c.patterns
 }
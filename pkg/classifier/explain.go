@@ -0,0 +1,37 @@
+package classifier
+
+import "database-classifier/internal/domain"
+
+// ExplainColumn classifies a column as ClassifyColumn does and returns every
+// pattern that matched it, best first, with how its score was made up.
+func (c *Classifier) ExplainColumn(column domain.ColumnContext) domain.ColumnExplanation {
+	explanation := domain.ColumnExplanation{
+		ColumnName:      column.ColumnName,
+		InformationType: domain.InfoTypeNA,
+		Matches:         []domain.PatternMatchExplanation{},
+	}
+	if column.ColumnName == "" {
+		return explanation
+	}
+
+	matches, contextRules := c.matchColumn(column)
+	for _, match := range matches {
+		explanation.Matches = append(explanation.Matches, domain.PatternMatchExplanation{
+			Pattern:         match.pattern.Pattern,
+			InformationType: match.pattern.InformationType,
+			Priority:        match.pattern.Priority,
+			Pack:            match.pattern.Pack,
+			MatchedPattern:  match.source,
+			MatchedText:     match.text,
+			ConfidenceScore: match.score,
+			Breakdown:       match.breakdown,
+		})
+	}
+	if len(matches) > 0 {
+		explanation.InformationType = matches[0].pattern.InformationType
+		explanation.ConfidenceScore = matches[0].score
+		explanation.ContextRules = contextRules
+	}
+
+	return explanation
+}
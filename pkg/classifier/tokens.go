@@ -0,0 +1,149 @@
+package classifier
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"database-classifier/internal/domain"
+)
+
+// TokenMatchPrefix marks the entries of MatchResult.MatchedPatterns for
+// patterns that matched the normalized tokens of a column name rather than
+// the name as written.
+const TokenMatchPrefix = "tokens:"
+
+// uncoveredTokenPenalty is subtracted from the score of a token match for
+// every token of the column name the match leaves out, so that
+// customer_email_address is an email address before it is an address.
+const uncoveredTokenPenalty = 0.1
+
+// FoldAccents strips the diacritics of s, e.g. Teléfono into Telefono and
+// Endereço into Endereco, so that names match patterns written with or
+// without them.
+func FoldAccents(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+func normalizeText(s string) string {
+	return strings.ToLower(strings.TrimSpace(FoldAccents(s)))
+}
+
+// Tokenize splits a column name into lower-cased tokens without accents at
+// separators, camelCase boundaries and between letters and digits, e.g.
+// CustomerEmailAddr into customer, email and addr, and HTTPProxy2 into http,
+// proxy and 2.
+func Tokenize(name string) []string {
+	runes := []rune(FoldAccents(name))
+
+	var tokens []string
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, strings.ToLower(string(runes[start:end])))
+			start = -1
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start >= 0 {
+			prev := runes[i-1]
+			switch {
+			case unicode.IsDigit(r) != unicode.IsDigit(prev):
+				flush(i)
+			case unicode.IsUpper(r) && unicode.IsLower(prev):
+				flush(i)
+			case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// The last capital of an acronym starts the next word
+				flush(i)
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return tokens
+}
+
+// SetAbbreviations replaces the dictionary used to expand column name tokens.
+func (c *Classifier) SetAbbreviations(abbreviations []*domain.Abbreviation) {
+	expansions := make(map[string][]string, len(abbreviations))
+	for _, a := range abbreviations {
+		expansions[strings.ToLower(a.Abbreviation)] = Tokenize(a.Expansion)
+	}
+	c.abbreviations = expansions
+}
+
+// NormalizeName tokenizes a column name and expands its abbreviations, e.g.
+// cust_first_nm into customer, first and name.
+func (c *Classifier) NormalizeName(name string) []string {
+	var normalized []string
+	for _, token := range Tokenize(name) {
+		if expansion, ok := c.abbreviations[token]; ok {
+			normalized = append(normalized, expansion...)
+		} else {
+			normalized = append(normalized, token)
+		}
+	}
+	return normalized
+}
+
+// matchTokens matches the pattern against the normalized tokens of a column
+// name joined with underscores, first all of them and then ever shorter runs
+// ending at the head of the name, and returns the first run that matches
+// along with the number of tokens it leaves out. The head is the last token
+// that is not a number: phone2 and billing_phone_no hold phone numbers, but
+// cell_count and email_template_id do not.
+func (p *Pattern) matchTokens(tokens []string) (string, int, bool) {
+	end := len(tokens)
+	for end > 1 && isNumber(tokens[end-1]) {
+		end--
+	}
+
+	if text := strings.Join(tokens, "_"); p.regex.MatchString(text) {
+		return text, 0, true
+	}
+	for start := 0; start < end; start++ {
+		if start == 0 && end == len(tokens) {
+			continue
+		}
+		text := strings.Join(tokens[start:end], "_")
+		if p.regex.MatchString(text) {
+			return text, len(tokens) - (end - start), true
+		}
+	}
+	return "", 0, false
+}
+
+func isNumber(token string) bool {
+	for _, r := range token {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return token != ""
+}
@@ -0,0 +1,112 @@
+package classifier
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"database-classifier/internal/domain"
+)
+
+// newStockClassifier builds a classifier from the patterns and abbreviations
+// shipped in configs.
+func newStockClassifier(t *testing.T) *Classifier {
+	t.Helper()
+
+	var patterns []*domain.ClassificationPattern
+	readConfig(t, "../../configs/patterns.json", &patterns)
+	var abbreviations []*domain.Abbreviation
+	readConfig(t, "../../configs/abbreviations.json", &abbreviations)
+
+	c, err := NewClassifier(patterns)
+	if err != nil {
+		t.Fatalf("NewClassifier: %v", err)
+	}
+	c.SetAbbreviations(abbreviations)
+	return c
+}
+
+func readConfig(t *testing.T, path string, v any) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"CustomerEmailAddr", []string{"customer", "email", "addr"}},
+		{"HTTPProxy2", []string{"http", "proxy", "2"}},
+		{"cust_first_nm", []string{"cust", "first", "nm"}},
+		{"Teléfono", []string{"telefono"}},
+	}
+
+	for _, tt := range tests {
+		got := Tokenize(tt.name)
+		if len(got) != len(tt.want) {
+			t.Errorf("Tokenize(%q) = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Tokenize(%q) = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestClassifyColumnTokenMatches(t *testing.T) {
+	c := newStockClassifier(t)
+
+	tests := []struct {
+		column string
+		want   domain.InformationType
+	}{
+		{"billingPhoneNo", domain.InfoTypePhoneNumber},
+		{"cust_first_nm", domain.InfoTypeFirstName},
+		{"customer_email_address", domain.InfoTypeEmailAddress},
+		{"phone2", domain.InfoTypePhoneNumber},
+	}
+
+	for _, tt := range tests {
+		result := c.ClassifyColumn(domain.ColumnContext{TableName: "customers", ColumnName: tt.column, DataType: "varchar"})
+		if result.InformationType != tt.want {
+			t.Errorf("%s classified as %s (%.2f), want %s", tt.column, result.InformationType, result.ConfidenceScore, tt.want)
+		}
+	}
+}
+
+// Names whose tokens contain an information type but that describe something
+// else about it must not be token matched.
+func TestClassifyColumnTokenMatchesRequireHead(t *testing.T) {
+	c := newStockClassifier(t)
+
+	tests := []struct {
+		column   string
+		dataType string
+		notWant  domain.InformationType
+	}{
+		{"cell_count", "int", domain.InfoTypePhoneNumber},
+		{"first_name_length", "int", domain.InfoTypeFirstName},
+		{"zip_file", "varchar", domain.InfoTypePostalCode},
+		{"tel_extension_enabled", "tinyint", domain.InfoTypePhoneNumber},
+		{"dob_verified", "tinyint", domain.InfoTypeDateOfBirth},
+		{"email_template_id", "bigint", domain.InfoTypeEmailAddress},
+	}
+
+	for _, tt := range tests {
+		result := c.ClassifyColumn(domain.ColumnContext{TableName: "customers", ColumnName: tt.column, DataType: tt.dataType})
+		if result.InformationType == tt.notWant {
+			t.Errorf("%s classified as %s (%.2f)", tt.column, result.InformationType, result.ConfidenceScore)
+		}
+	}
+}
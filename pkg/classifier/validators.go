@@ -0,0 +1,321 @@
+package classifier
+
+import (
+	"net"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Validator reports whether a single sampled value is a structurally valid
+// instance of an information type, beyond what its regular expression checks.
+type Validator func(value string) bool
+
+var validators = map[string]Validator{
+	"luhn":        validateLuhn,
+	"iban":        validateIBAN,
+	"us_ssn":      validateUSSSN,
+	"aba_routing": validateABARouting,
+	"ipv4":        validateIPv4,
+	"ipv6":        validateIPv6,
+	"ip":          validateIP,
+	"mac":         validateMAC,
+	"email":       validateEmail,
+	"phone":       validatePhone,
+	"br_cpf":      validateBRCPF,
+	"cl_rut":      validateCLRUT,
+	"ar_cuit":     validateARCUIT,
+	"es_dni":      validateESDNI,
+	"mx_curp":     validateMXCURP,
+	"mx_rfc":      validateMXRFC,
+	"co_nit":      validateCONIT,
+}
+
+func LookupValidator(name string) (Validator, bool) {
+	v, ok := validators[name]
+	return v, ok
+}
+
+func ValidatorNames() []string {
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func digitsOnly(value string, separators string) (string, bool) {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(separators, r):
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+func validateLuhn(value string) bool {
+	digits, ok := digitsOnly(value, " -")
+	if !ok || len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func validateIBAN(value string) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	for i, r := range iban {
+		isLetter := r >= 'A' && r <= 'Z'
+		isDigit := r >= '0' && r <= '9'
+		if (i < 2 && !isLetter) || (i >= 2 && i < 4 && !isDigit) || (!isLetter && !isDigit) {
+			return false
+		}
+	}
+
+	// ISO 13616: move the country code and check digits to the end, map
+	// letters to 10..35 and verify the number is congruent to 1 mod 97.
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			n := int(r-'A') + 10
+			remainder = (remainder*100 + n) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
+
+func validateUSSSN(value string) bool {
+	digits, ok := digitsOnly(value, "- ")
+	if !ok || len(digits) != 9 {
+		return false
+	}
+
+	area, group, serial := digits[:3], digits[3:5], digits[5:]
+	if area == "000" || area == "666" || area[0] == '9' {
+		return false
+	}
+	return group != "00" && serial != "0000"
+}
+
+func validateABARouting(value string) bool {
+	digits, ok := digitsOnly(value, "")
+	if !ok || len(digits) != 9 {
+		return false
+	}
+
+	prefix := int(digits[0]-'0')*10 + int(digits[1]-'0')
+	validPrefix := prefix <= 12 || (prefix >= 21 && prefix <= 32) || (prefix >= 61 && prefix <= 72) || prefix == 80
+	if !validPrefix {
+		return false
+	}
+
+	weights := [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+	return sum%10 == 0
+}
+
+func validateIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && strings.Contains(value, ".") && !strings.Contains(value, ":")
+}
+
+func validateIPv6(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && strings.Contains(value, ":")
+}
+
+func validateIP(value string) bool {
+	return net.ParseIP(value) != nil
+}
+
+func validateMAC(value string) bool {
+	hw, err := net.ParseMAC(value)
+	return err == nil && len(hw) == 6
+}
+
+func validateEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Address == value
+}
+
+func validatePhone(value string) bool {
+	digits, ok := digitsOnly(value, "+ ().-")
+	return ok && len(digits) >= 7 && len(digits) <= 15
+}
+
+// validateBRCPF checks the two check digits of a Brazilian CPF, written as
+// 123.456.789-09 or as bare digits.
+func validateBRCPF(value string) bool {
+	digits, ok := digitsOnly(value, ".- ")
+	if !ok || len(digits) != 11 || strings.Count(digits, digits[:1]) == 11 {
+		return false
+	}
+
+	for n := 9; n <= 10; n++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += int(digits[i]-'0') * (n + 1 - i)
+		}
+		check := sum * 10 % 11 % 10
+		if check != int(digits[n]-'0') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCLRUT checks the modulo 11 check digit of a Chilean RUT, written as
+// 12.345.678-5 or 12345678K. Older RUTs have a body of 6 or 7 digits.
+func validateCLRUT(value string) bool {
+	rut := strings.ToUpper(strings.NewReplacer(".", "", "-", "", " ", "").Replace(value))
+	if len(rut) < 7 || len(rut) > 9 {
+		return false
+	}
+	body, check := rut[:len(rut)-1], rut[len(rut)-1]
+	if _, ok := digitsOnly(body, ""); !ok {
+		return false
+	}
+
+	sum, weight := 0, 2
+	for i := len(body) - 1; i >= 0; i-- {
+		sum += int(body[i]-'0') * weight
+		if weight++; weight > 7 {
+			weight = 2
+		}
+	}
+	switch expected := 11 - sum%11; expected {
+	case 11:
+		return check == '0'
+	case 10:
+		return check == 'K'
+	default:
+		return check == byte('0'+expected)
+	}
+}
+
+// validateARCUIT checks the modulo 11 check digit of an Argentine CUIT or
+// CUIL, written as 20-12345678-6 or as bare digits.
+func validateARCUIT(value string) bool {
+	digits, ok := digitsOnly(value, "- ")
+	if !ok || len(digits) != 11 {
+		return false
+	}
+
+	weights := [10]int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+	expected := 11 - sum%11
+	if expected == 11 {
+		expected = 0
+	}
+	return expected < 10 && expected == int(digits[10]-'0')
+}
+
+// validateESDNI checks the control letter of a Spanish DNI, 12345678Z, or
+// NIE, X1234567L.
+func validateESDNI(value string) bool {
+	id := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(value))
+	if len(id) != 9 {
+		return false
+	}
+	if i := strings.IndexByte("XYZ", id[0]); i >= 0 {
+		id = string(rune('0'+i)) + id[1:]
+	}
+	digits, ok := digitsOnly(id[:8], "")
+	if !ok {
+		return false
+	}
+
+	n := 0
+	for _, r := range digits {
+		n = n*10 + int(r-'0')
+	}
+	return id[8] == "TRWAGMYFPDXBNJZSQVHLCKE"[n%23]
+}
+
+var curpRegex = regexp.MustCompile(`^[A-Z][AEIOUX][A-Z]{2}\d{2}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])[HMX][A-Z]{2}[B-DF-HJ-NP-TV-Z]{3}[A-Z\d]\d$`)
+
+// validateMXCURP checks the format and the check digit of a Mexican CURP.
+func validateMXCURP(value string) bool {
+	curp := strings.ToUpper(strings.TrimSpace(value))
+	if !curpRegex.MatchString(curp) {
+		return false
+	}
+
+	// Ñ, which the format excludes, keeps its place after N as &
+	const alphabet = "0123456789ABCDEFGHIJKLMN&OPQRSTUVWXYZ"
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += strings.IndexByte(alphabet, curp[i]) * (18 - i)
+	}
+	return (10-sum%10)%10 == int(curp[17]-'0')
+}
+
+var rfcRegex = regexp.MustCompile(`^[A-Z&Ñ]{3,4}\d{2}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])[A-Z\d]{2}[A\d]$`)
+
+// validateMXRFC checks the format of a Mexican RFC of a person, 13
+// characters, or of a company, 12; its homoclave is not verified.
+func validateMXRFC(value string) bool {
+	rfc := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(value))
+	return rfcRegex.MatchString(rfc)
+}
+
+// validateCONIT checks a Colombian NIT. Written with its verification digit,
+// as 900.123.456-8, the digit is checked; bare numbers are only checked for
+// length.
+func validateCONIT(value string) bool {
+	body, check, hasCheck := strings.Cut(value, "-")
+	digits, ok := digitsOnly(body, ". ")
+	if !ok || len(digits) < 8 || len(digits) > 10 {
+		return false
+	}
+	if !hasCheck {
+		return true
+	}
+	check = strings.TrimSpace(check)
+	if len(check) != 1 || check[0] < '0' || check[0] > '9' {
+		return false
+	}
+
+	weights := [...]int{3, 7, 13, 17, 19, 23, 29, 37, 41, 43}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[len(digits)-1-i]-'0') * weights[i]
+	}
+	expected := sum % 11
+	if expected > 1 {
+		expected = 11 - expected
+	}
+	return expected == int(check[0]-'0')
+}
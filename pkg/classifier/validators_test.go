@@ -0,0 +1,99 @@
+package classifier
+
+import "testing"
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		validator string
+		value     string
+		want      bool
+	}{
+		{"luhn", "4111111111111111", true},
+		{"luhn", "4539 1488 0343 6467", true},
+		{"luhn", "5500-0000-0000-0004", true},
+		{"luhn", "4111111111111112", false},
+		{"luhn", "411111111111", false},
+		{"luhn", "4111-1111-1111-111a", false},
+
+		{"iban", "GB82 WEST 1234 5698 7654 32", true},
+		{"iban", "DE89370400440532013000", true},
+		{"iban", "es9121000418450200051332", true},
+		{"iban", "GB82WEST12345698765433", false},
+		{"iban", "1282WEST12345698765432", false},
+		{"iban", "GB82WEST", false},
+
+		{"us_ssn", "123-45-6789", true},
+		{"us_ssn", "123456789", true},
+		{"us_ssn", "000-12-3456", false},
+		{"us_ssn", "666-12-3456", false},
+		{"us_ssn", "900-12-3456", false},
+		{"us_ssn", "123-00-4567", false},
+		{"us_ssn", "123-45-0000", false},
+		{"us_ssn", "123-45-678", false},
+
+		{"aba_routing", "011000015", true},
+		{"aba_routing", "021000021", true},
+		{"aba_routing", "122105155", true},
+		{"aba_routing", "021000022", false},
+		{"aba_routing", "991000025", false},
+		{"aba_routing", "02100002", false},
+
+		{"br_cpf", "529.982.247-25", true},
+		{"br_cpf", "52998224725", true},
+		{"br_cpf", "529.982.247-24", false},
+		{"br_cpf", "111.111.111-11", false},
+		{"br_cpf", "5299822472", false},
+
+		{"cl_rut", "12.345.678-5", true},
+		{"cl_rut", "12345678-5", true},
+		{"cl_rut", "1.000.005-K", true},
+		{"cl_rut", "1000005k", true},
+		{"cl_rut", "123.456-0", true},
+		{"cl_rut", "12.345.678-K", false},
+		{"cl_rut", "123.456-1", false},
+		{"cl_rut", "12.345-0", false},
+		{"cl_rut", "123.456.789-0", false},
+
+		{"ar_cuit", "20-12345678-6", true},
+		{"ar_cuit", "27333333339", true},
+		{"ar_cuit", "30-71234567-1", true},
+		{"ar_cuit", "20-12345678-5", false},
+		{"ar_cuit", "20-1234567-6", false},
+
+		{"es_dni", "12345678Z", true},
+		{"es_dni", "12345678-z", true},
+		{"es_dni", "X1234567L", true},
+		{"es_dni", "12345678A", false},
+		{"es_dni", "X1234567Z", false},
+		{"es_dni", "1234567Z", false},
+
+		{"mx_curp", "GODE561231HDFRRN00", true},
+		{"mx_curp", "badd110313hcmlns06", true},
+		{"mx_curp", "GODE561231HDFRRN01", false},
+		{"mx_curp", "GODE561331HDFRRN00", false},
+		{"mx_curp", "GODE561231HDFRRN0", false},
+
+		{"mx_rfc", "GODE561231GR8", true},
+		{"mx_rfc", "ABC680524P76", true},
+		{"mx_rfc", "GODE-561231-GR8", true},
+		{"mx_rfc", "GODE561331GR8", false},
+		{"mx_rfc", "GO561231GR8", false},
+
+		{"co_nit", "900.123.456-8", true},
+		{"co_nit", "860002964-4", true},
+		{"co_nit", "900123456", true},
+		{"co_nit", "900.123.456-7", false},
+		{"co_nit", "900.123.456-", false},
+		{"co_nit", "1234567", false},
+	}
+
+	for _, tt := range tests {
+		validate, ok := LookupValidator(tt.validator)
+		if !ok {
+			t.Fatalf("validator %s is not registered", tt.validator)
+		}
+		if got := validate(tt.value); got != tt.want {
+			t.Errorf("%s(%q) = %t, want %t", tt.validator, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package classifier
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"database-classifier/internal/domain"
+)
+
+// minValueMatchRatio is the share of sampled values that must match a detector
+// before the column is attributed to its information type.
+const minValueMatchRatio = 0.5
+
+type ValueDetector struct {
+	Name            string                 `json:"name"`
+	InformationType domain.InformationType `json:"information_type"`
+	Pattern         string                 `json:"pattern"`
+	Validator       string                 `json:"validator,omitempty"`
+	// Exclude matches values that fit Pattern but are something else, such as
+	// dates written like phone numbers.
+	Exclude string `json:"exclude,omitempty"`
+	// DisallowedDataTypes are the lower-cased base types of columns the
+	// detector is not run on.
+	DisallowedDataTypes []string `json:"disallowed_data_types,omitempty"`
+	regex               *regexp.Regexp
+	exclude             *regexp.Regexp
+	validate            Validator
+}
+
+type ValueMatchResult struct {
+	InformationType domain.InformationType
+	ConfidenceScore float64
+	SampleSize      int
+	MatchedSamples  int
+	Detector        string
+}
+
+// defaultValueDetectors are evaluated in order; when two detectors match the
+// same share of values the earlier, more specific one wins.
+var defaultValueDetectors = []ValueDetector{
+	{Name: "email", InformationType: domain.InfoTypeEmailAddress, Pattern: `^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`, Validator: "email"},
+	{Name: "credit_card", InformationType: domain.InfoTypeCreditCardNumber, Pattern: `^(?:[0-9][ -]?){12,18}[0-9]$`, Validator: "luhn"},
+	{Name: "ssn", InformationType: domain.InfoTypeSSN, Pattern: `^[0-9]{3}-[0-9]{2}-[0-9]{4}$`, Validator: "us_ssn"},
+	{Name: "iban", InformationType: domain.InfoTypeBankAccount, Pattern: `^[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]){11,30}$`, Validator: "iban"},
+	{Name: "aba_routing", InformationType: domain.InfoTypeBankAccount, Pattern: `^[0-9]{9}$`, Validator: "aba_routing"},
+	{Name: "mac", InformationType: domain.InfoTypeMACAddress, Pattern: `^(?:[0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`, Validator: "mac"},
+	{Name: "ipv4", InformationType: domain.InfoTypeIPAddress, Pattern: `^(?:(?:25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])$`, Validator: "ipv4"},
+	{Name: "ipv6", InformationType: domain.InfoTypeIPAddress, Pattern: `^(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}$`, Validator: "ipv6"},
+	// A phone value must start with + or have at least two separator groups;
+	// bare digit runs are as likely to be IDs or epoch seconds, one separator
+	// makes a decimal like a coordinate, and dates are excluded. Decimal
+	// columns hold amounts and coordinates rather than phone numbers.
+	{Name: "phone", InformationType: domain.InfoTypePhoneNumber,
+		Pattern:             `^(?:\+[0-9(][0-9 ().-]{5,18}[0-9]|[0-9(][0-9)]*[ ().-]+[0-9]+[ ().-]+[0-9][0-9 ().-]*[0-9])$`,
+		Exclude:             `^(?:[0-9]{4}[./-][0-9]{1,2}[./-][0-9]{1,2}|[0-9]{1,2}[./-][0-9]{1,2}[./-][0-9]{2,4})$`,
+		Validator:           "phone",
+		DisallowedDataTypes: []string{"decimal", "numeric"}},
+}
+
+func DefaultValueDetectors() ([]ValueDetector, error) {
+	detectors := make([]ValueDetector, 0, len(defaultValueDetectors))
+	for _, d := range defaultValueDetectors {
+		regex, err := regexp.Compile(d.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile value detector '%s': %w", d.Name, err)
+		}
+		if d.Exclude != "" {
+			exclude, err := regexp.Compile(d.Exclude)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile exclusion of value detector '%s': %w", d.Name, err)
+			}
+			d.exclude = exclude
+		}
+		validate, err := resolveValidator(d.Validator)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value detector '%s': %w", d.Name, err)
+		}
+		d.regex = regex
+		d.validate = validate
+		detectors = append(detectors, d)
+	}
+	return detectors, nil
+}
+
+// ClassifyValues attributes the sampled values of a column with the given data
+// type to the detector that matches the most of them.
+func (c *Classifier) ClassifyValues(dataType string, values []string) ValueMatchResult {
+	result := ValueMatchResult{InformationType: domain.InfoTypeNA}
+
+	samples := make([]string, 0, len(values))
+	for _, v := range values {
+		if trimmed := strings.TrimSpace(v); trimmed != "" {
+			samples = append(samples, trimmed)
+		}
+	}
+	result.SampleSize = len(samples)
+	if len(samples) == 0 {
+		return result
+	}
+
+	dataType = baseDataType(dataType)
+	best := -1
+	bestCount := 0
+	for i, detector := range c.detectors {
+		if dataType != "" && containsString(detector.DisallowedDataTypes, dataType) {
+			continue
+		}
+		count := 0
+		for _, sample := range samples {
+			if detector.matches(sample) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best = i
+			bestCount = count
+		}
+	}
+
+	if best < 0 {
+		return result
+	}
+
+	ratio := float64(bestCount) / float64(len(samples))
+	if ratio < minValueMatchRatio {
+		return result
+	}
+
+	result.InformationType = c.detectors[best].InformationType
+	result.ConfidenceScore = ratio
+	result.MatchedSamples = bestCount
+	result.Detector = c.detectors[best].Name
+	return result
+}
+
+func (d *ValueDetector) matches(value string) bool {
+	return d.regex.MatchString(value) &&
+		(d.exclude == nil || !d.exclude.MatchString(value)) &&
+		(d.validate == nil || d.validate(value))
+}
+
+// ValidateSamples counts the non-empty values that pass the validator
+// referenced by the given pattern, which may carry CommentMatchPrefix or
+// TokenMatchPrefix. ok is false when the pattern declares no validator.
+func (c *Classifier) ValidateSamples(patternStr string, values []string) (passed int, total int, ok bool) {
+	patternStr = strings.TrimPrefix(strings.TrimPrefix(patternStr, CommentMatchPrefix), TokenMatchPrefix)
+	var validate Validator
+	for _, p := range c.patterns {
+		if p.Pattern == patternStr && p.validate != nil {
+			validate = p.validate
+			break
+		}
+	}
+	if validate == nil {
+		return 0, 0, false
+	}
+
+	for _, v := range values {
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			continue
+		}
+		total++
+		if validate(trimmed) {
+			passed++
+		}
+	}
+	return passed, total, true
+}
+
+func (c *Classifier) GetValueDetectors() []ValueDetector {
+	return c.detectors
+}
@@ -0,0 +1,35 @@
+package classifier
+
+import (
+	"testing"
+
+	"database-classifier/internal/domain"
+)
+
+func TestClassifyValuesPhoneNumbers(t *testing.T) {
+	c := newStockClassifier(t)
+
+	tests := []struct {
+		name     string
+		dataType string
+		values   []string
+		want     domain.InformationType
+	}{
+		{"international", "varchar", []string{"+1 555 123 4567", "+34 612 345 678", "+44 20 7946 0958"}, domain.InfoTypePhoneNumber},
+		{"grouped", "varchar", []string{"555-123-4567", "(555) 123-4567", "555.123.4567"}, domain.InfoTypePhoneNumber},
+		{"bare digits", "bigint", []string{"5551234567", "1700000000", "4815162342"}, domain.InfoTypeNA},
+		{"coordinates", "varchar", []string{"40.712776", "-73.935242", "51.507351"}, domain.InfoTypeNA},
+		{"coordinates in a decimal column", "decimal(9,6)", []string{"40.712776", "-73.935242", "51.507351"}, domain.InfoTypeNA},
+		{"ISO dates", "varchar", []string{"2024-01-15", "1987-11-03", "2001-07-30"}, domain.InfoTypeNA},
+		{"day-first dates", "varchar", []string{"15/01/2024", "03.11.1987", "30-07-2001"}, domain.InfoTypeNA},
+		{"grouped numbers in a numeric column", "numeric", []string{"555-123-4567", "555-987-6543"}, domain.InfoTypeNA},
+	}
+
+	for _, tt := range tests {
+		result := c.ClassifyValues(tt.dataType, tt.values)
+		if result.InformationType != tt.want {
+			t.Errorf("%s: ClassifyValues(%q, %v) = %s (%.2f, %s), want %s",
+				tt.name, tt.dataType, tt.values, result.InformationType, result.ConfidenceScore, result.Detector, tt.want)
+		}
+	}
+}